@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/formatting"
+	"github.com/docker/mcp-gateway/pkg/audit"
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+const DefaultFilename = "audit.jsonl"
+
+// Query prints the records of the audit log that match the filter.
+// A positive limit only keeps the most recent records.
+func Query(file string, filter audit.Filter, limit int, outputJSON bool) error {
+	path, err := config.FilePath(file)
+	if err != nil {
+		return err
+	}
+
+	records, err := audit.Read(path, filter)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	if outputJSON {
+		if len(records) == 0 {
+			records = make([]audit.Record, 0) // Guarantee empty list (instead of displaying null)
+		}
+		jsonData, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	var rows [][]string
+	for _, record := range records {
+		rows = append(rows, []string{
+			record.Timestamp.Local().Format(time.RFC3339),
+			record.Operation,
+			record.Client,
+			record.Server,
+			target(record),
+			record.Status,
+			strconv.FormatInt(record.DurationMs, 10) + "ms",
+		})
+	}
+	formatting.PrettyPrintTable(rows, []int{25, 15, 20, 30, 60, 10, 10})
+	return nil
+}
+
+func target(record audit.Record) string {
+	switch {
+	case record.Tool != "":
+		return record.Tool
+	case record.Prompt != "":
+		return record.Prompt
+	default:
+		return record.Resource
+	}
+}
+
+// ParseTime parses either an absolute RFC3339 time or a duration relative to now.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use a duration (e.g. 1h) or an RFC3339 timestamp", value)
+	}
+
+	return now.Add(-duration), nil
+}
@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	since, err := ParseTime("", now)
+	require.NoError(t, err)
+	assert.True(t, since.IsZero())
+
+	since, err = ParseTime("90m", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 1, 10, 30, 0, 0, time.UTC), since)
+
+	since, err = ParseTime("2025-08-31T08:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 31, 8, 0, 0, 0, time.UTC), since)
+
+	_, err = ParseTime("yesterday", now)
+	require.Error(t, err)
+}
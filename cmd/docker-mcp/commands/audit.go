@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/audit"
+	auditlog "github.com/docker/mcp-gateway/pkg/audit"
+)
+
+func auditCommand() *cobra.Command {
+	var opts struct {
+		File      string
+		Operation string
+		Client    string
+		Session   string
+		Server    string
+		Tool      string
+		Status    string
+		Since     string
+		Until     string
+		Limit     int
+		JSON      bool
+	}
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the audit log of the gateway",
+		Long:  "Query the audit log written by `docker mcp gateway run --audit-log`.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			now := time.Now()
+			since, err := audit.ParseTime(opts.Since, now)
+			if err != nil {
+				return err
+			}
+			until, err := audit.ParseTime(opts.Until, now)
+			if err != nil {
+				return err
+			}
+
+			filter := auditlog.Filter{
+				Operation: opts.Operation,
+				Client:    opts.Client,
+				Session:   opts.Session,
+				Server:    opts.Server,
+				Tool:      opts.Tool,
+				Status:    opts.Status,
+				Since:     since,
+				Until:     until,
+			}
+			return audit.Query(opts.File, filter, opts.Limit, opts.JSON)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.File, "file", audit.DefaultFilename, "Path to the audit log (absolute or relative to ~/.docker/mcp/)")
+	flags.StringVar(&opts.Operation, "operation", "", "Only show one type of operation (tools/call, prompts/get or resources/read)")
+	flags.StringVar(&opts.Client, "client", "", "Only show operations from this client")
+	flags.StringVar(&opts.Session, "session", "", "Only show operations from this session")
+	flags.StringVar(&opts.Server, "server", "", "Only show operations on matching servers (supports glob patterns)")
+	flags.StringVar(&opts.Tool, "tool", "", "Only show calls to matching tools (supports glob patterns)")
+	flags.StringVar(&opts.Status, "status", "", "Only show operations with this status (success, error or failure)")
+	flags.StringVar(&opts.Since, "since", "", "Only show operations after this time (duration such as 1h or RFC3339 timestamp)")
+	flags.StringVar(&opts.Until, "until", "", "Only show operations before this time (duration such as 1h or RFC3339 timestamp)")
+	flags.IntVar(&opts.Limit, "limit", 0, "Only show the most recent operations")
+	flags.BoolVar(&opts.JSON, "json", false, "Print as JSON.")
+	return cmd
+}
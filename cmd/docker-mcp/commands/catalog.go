@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
 	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/yq"
 )
 
@@ -36,6 +37,7 @@ func catalogCommand() *cobra.Command {
 
 func importCatalogCommand() *cobra.Command {
 	var mcpRegistry string
+	var prefer string
 	var dryRun bool
 	cmd := &cobra.Command{
 		Use:   "import <alias|url|file>",
@@ -53,20 +55,28 @@ command will import servers from the MCP registry URL into that catalog.`,
   docker mcp catalog import ./shared-catalog.yaml
   
   # Import from MCP registry URL into existing catalog
-  docker mcp catalog import my-catalog --mcp-registry https://registry.example.com/server`,
+  docker mcp catalog import my-catalog --mcp-registry https://registry.example.com/server
+
+  # Import the remote of an MCP registry server rather than its package
+  docker mcp catalog import my-catalog --mcp-registry https://registry.example.com/server --prefer package`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If mcp-registry flag is provided, import to existing catalog
 			if mcpRegistry != "" {
+				source, err := oci.ParseSource(prefer)
+				if err != nil {
+					return err
+				}
 				if dryRun {
-					return runMcpregistryImport(cmd.Context(), mcpRegistry, nil)
+					return runMcpregistryImport(cmd.Context(), mcpRegistry, source, nil)
 				}
-				return importMCPRegistryToCatalog(cmd.Context(), args[0], mcpRegistry)
+				return importMCPRegistryToCatalog(cmd.Context(), args[0], mcpRegistry, source)
 			}
 			// Default behavior: import entire catalog
 			return catalog.Import(cmd.Context(), args[0])
 		},
 	}
 	cmd.Flags().StringVar(&mcpRegistry, "mcp-registry", "", "Import server from MCP registry URL into existing catalog")
+	cmd.Flags().StringVar(&prefer, "prefer", string(oci.SourceRemote), "What to run when the MCP registry server is published both as a package and as a remote: package or remote")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show Imported Data but do not update the Catalog")
 	return cmd
 }
@@ -262,7 +272,7 @@ func resetCatalogCommand() *cobra.Command {
 }
 
 // importMCPRegistryToCatalog imports a server from an MCP registry URL into an existing catalog
-func importMCPRegistryToCatalog(ctx context.Context, catalogName, mcpRegistryURL string) error {
+func importMCPRegistryToCatalog(ctx context.Context, catalogName, mcpRegistryURL string, source oci.Source) error {
 	// Check if the catalog exists
 	cfg, err := catalog.ReadConfig()
 	if err != nil {
@@ -281,7 +291,7 @@ func importMCPRegistryToCatalog(ctx context.Context, catalogName, mcpRegistryURL
 
 	// Fetch server from MCP registry
 	var servers []catalogTypes.Server
-	if err := runMcpregistryImport(ctx, mcpRegistryURL, &servers); err != nil {
+	if err := runMcpregistryImport(ctx, mcpRegistryURL, source, &servers); err != nil {
 		return fmt.Errorf("failed to fetch server from MCP registry: %w", err)
 	}
 
@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
+	"github.com/docker/mcp-gateway/pkg/budget"
 	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/oci"
 )
 
 func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
@@ -28,36 +31,59 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	var additionalConfigs []string
 	var additionalToolsConfig []string
 	var mcpRegistryUrls []string
+	var mcpRegistryPrefer string
 	var enableAllServers bool
 	if os.Getenv("DOCKER_MCP_IN_CONTAINER") == "1" {
 		// In-container.
 		options = gateway.Config{
-			CatalogPath: []string{catalog.DockerCatalogURL},
-			SecretsPath: "docker-desktop:/run/secrets/mcp_secret:/.env",
+			CatalogPath:   []string{catalog.DockerCatalogURL},
+			PromptsPath:   []string{"prompts.yaml"},
+			ResourcesPath: []string{"resources.yaml"},
+			SecretsPath:   "docker-desktop:/run/secrets/mcp_secret:/.env",
 			Options: gateway.Options{
-				Cpus:         1,
-				Memory:       "2Gb",
-				Transport:    "stdio",
-				LogCalls:     true,
-				BlockSecrets: true,
-				Verbose:      true,
+				Cpus:                  1,
+				Memory:                "2Gb",
+				Transport:             "stdio",
+				LogCalls:              true,
+				BlockSecrets:          true,
+				Verbose:               true,
+				AuditLogMaxSize:       10,
+				AuditLogMaxBackups:    5,
+				AuditArguments:        "digest",
+				ValidateOutput:        gateway.OutputValidationOff,
+				OffloadTTL:            30 * time.Minute,
+				OffloadPageSize:       64 * 1024,
+				OffloadMaxSize:        256,
+				ToolsBudgetStrategies: []string{budget.StrategyDescriptions, budget.StrategySchemaDescriptions},
+				ToolsBudgetWindow:     7 * 24 * time.Hour,
 			},
 		}
 	} else {
 		// On-host.
 		options = gateway.Config{
-			CatalogPath:  []string{catalog.DockerCatalogFilename},
-			RegistryPath: []string{"registry.yaml"},
-			ConfigPath:   []string{"config.yaml"},
-			ToolsPath:    []string{"tools.yaml"},
-			SecretsPath:  "docker-desktop",
+			CatalogPath:   []string{catalog.DockerCatalogFilename},
+			RegistryPath:  []string{"registry.yaml"},
+			ConfigPath:    []string{"config.yaml"},
+			ToolsPath:     []string{"tools.yaml"},
+			PromptsPath:   []string{"prompts.yaml"},
+			ResourcesPath: []string{"resources.yaml"},
+			SecretsPath:   "docker-desktop",
 			Options: gateway.Options{
-				Cpus:         1,
-				Memory:       "2Gb",
-				Transport:    "stdio",
-				LogCalls:     true,
-				BlockSecrets: true,
-				Watch:        true,
+				Cpus:                  1,
+				Memory:                "2Gb",
+				Transport:             "stdio",
+				LogCalls:              true,
+				BlockSecrets:          true,
+				Watch:                 true,
+				AuditLogMaxSize:       10,
+				AuditLogMaxBackups:    5,
+				AuditArguments:        "digest",
+				ValidateOutput:        gateway.OutputValidationOff,
+				OffloadTTL:            30 * time.Minute,
+				OffloadPageSize:       64 * 1024,
+				OffloadMaxSize:        256,
+				ToolsBudgetStrategies: []string{budget.StrategyDescriptions, budget.StrategySchemaDescriptions},
+				ToolsBudgetWindow:     7 * 24 * time.Hour,
 			},
 		}
 	}
@@ -85,6 +111,28 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 				options.Transport = "streaming"
 			}
 
+			if options.AuditArguments != "digest" && options.AuditArguments != "redacted" {
+				return fmt.Errorf("invalid --audit-arguments %q: must be digest or redacted", options.AuditArguments)
+			}
+
+			switch options.ValidateOutput {
+			case gateway.OutputValidationOff, gateway.OutputValidationWarn, gateway.OutputValidationStrip, gateway.OutputValidationFail:
+			default:
+				return fmt.Errorf("invalid --validate-output %q: must be off, warn, strip or fail", options.ValidateOutput)
+			}
+
+			if err := budget.ValidateStrategies(options.ToolsBudgetStrategies); err != nil {
+				return fmt.Errorf("invalid --tools-budget-strategies: %w", err)
+			}
+
+			if options.OffloadThreshold > 0 && options.OffloadTTL <= 0 {
+				return fmt.Errorf("invalid --offload-ttl %s: must be positive", options.OffloadTTL)
+			}
+
+			if options.RecordDir != "" && options.ReplayDir != "" {
+				return errors.New("cannot use --record-dir with --replay-dir")
+			}
+
 			if options.Transport == "stdio" {
 				if options.Port != 0 {
 					return errors.New("cannot use --port with --transport=stdio")
@@ -108,11 +156,17 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 			options.ConfigPath = append(options.ConfigPath, additionalConfigs...)
 			options.ToolsPath = append(options.ToolsPath, additionalToolsConfig...)
 
+			source, err := oci.ParseSource(mcpRegistryPrefer)
+			if err != nil {
+				return err
+			}
+			options.MCPRegistryPrefer = source
+
 			// Process MCP registry URLs if provided
 			if len(mcpRegistryUrls) > 0 {
 				var mcpServers []catalogTypes.Server
 				for _, registryURL := range mcpRegistryUrls {
-					if err := runMcpregistryImport(cmd.Context(), registryURL, &mcpServers); err != nil {
+					if err := runMcpregistryImport(cmd.Context(), registryURL, source, &mcpServers); err != nil {
 						return fmt.Errorf("failed to fetch server from MCP registry %s: %w", registryURL, err)
 					}
 				}
@@ -155,9 +209,14 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	runCmd.Flags().StringSliceVar(&additionalToolsConfig, "additional-tools-config", nil, "Additional tools paths to merge with the default tools.yaml")
 	runCmd.Flags().StringVar(&options.SecretsPath, "secrets", options.SecretsPath, "Colon separated paths to search for secrets. Can be `docker-desktop` or a path to a .env file (default to using Docker Desktop's secrets API)")
 	runCmd.Flags().StringSliceVar(&options.ToolNames, "tools", options.ToolNames, "List of tools to enable")
+	runCmd.Flags().StringSliceVar(&options.PromptsPath, "prompts-config", options.PromptsPath, "Paths to the prompts files (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringSliceVar(&options.PromptNames, "prompts", options.PromptNames, "List of prompts to enable")
+	runCmd.Flags().StringSliceVar(&options.ResourcesPath, "resources-config", options.ResourcesPath, "Paths to the resources files (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringSliceVar(&options.ResourceNames, "resources", options.ResourceNames, "List of resources and resource templates to enable, by URI or name")
 	runCmd.Flags().StringArrayVar(&options.Interceptors, "interceptor", options.Interceptors, "List of interceptors to use (format: when:type:path, e.g. 'before:exec:/bin/path')")
 	runCmd.Flags().StringArrayVar(&options.OciRef, "oci-ref", options.OciRef, "OCI image references to use")
 	runCmd.Flags().StringSliceVar(&mcpRegistryUrls, "mcp-registry", nil, "MCP registry URLs to fetch servers from (can be repeated)")
+	runCmd.Flags().StringVar(&mcpRegistryPrefer, "mcp-registry-prefer", string(oci.SourceRemote), "What to run when an MCP registry server, from --mcp-registry, --oci-ref or the mcp-registry-import tool, is published both as a package and as a remote: package or remote")
 	runCmd.Flags().IntVar(&options.Port, "port", options.Port, "TCP port to listen on (default is to listen on stdio)")
 	runCmd.Flags().StringVar(&options.Transport, "transport", options.Transport, "stdio, sse or streaming (default is stdio)")
 	runCmd.Flags().BoolVar(&options.LogCalls, "log-calls", options.LogCalls, "Log calls to the tools")
@@ -169,9 +228,36 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	runCmd.Flags().BoolVar(&options.LongLived, "long-lived", options.LongLived, "Containers are long-lived and will not be removed until the gateway is stopped, useful for stateful servers")
 	runCmd.Flags().BoolVar(&options.DebugDNS, "debug-dns", options.DebugDNS, "Debug DNS resolution")
 	runCmd.Flags().BoolVar(&options.Watch, "watch", options.Watch, "Watch for changes and reconfigure the gateway")
-	runCmd.Flags().IntVar(&options.Cpus, "cpus", options.Cpus, "CPUs allocated to each MCP Server (default is 1)")
-	runCmd.Flags().StringVar(&options.Memory, "memory", options.Memory, "Memory allocated to each MCP Server (default is 2Gb)")
+	runCmd.Flags().IntVar(&options.Cpus, "cpus", options.Cpus, "Maximum CPUs allocated to each MCP Server, even if its catalog entry or config asks for more (default is 1)")
+	runCmd.Flags().StringVar(&options.Memory, "memory", options.Memory, "Maximum memory allocated to each MCP Server, even if its catalog entry or config asks for more (default is 2Gb)")
+	runCmd.Flags().StringVar(&options.SeccompProfile, "seccomp-profile", options.SeccompProfile, "Path to a custom seccomp profile for the MCP Servers")
+	runCmd.Flags().BoolVar(&options.PackagesOnHost, "packages-on-host", options.PackagesOnHost, "Run the MCP Servers published as npm or PyPI packages, or as binaries, directly on the host instead of in a runtime container")
 	runCmd.Flags().BoolVar(&options.Static, "static", options.Static, "Enable static mode (aka pre-started servers)")
+	runCmd.Flags().StringVar(&options.AuditLog, "audit-log", options.AuditLog, "Path to the audit log of tool calls, prompt gets and resource reads (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().IntVar(&options.AuditLogMaxSize, "audit-log-max-size", options.AuditLogMaxSize, "Maximum size in megabytes of the audit log before it gets rotated")
+	runCmd.Flags().IntVar(&options.AuditLogMaxBackups, "audit-log-max-backups", options.AuditLogMaxBackups, "Maximum number of rotated audit logs to keep")
+	runCmd.Flags().StringVar(&options.AuditArguments, "audit-arguments", options.AuditArguments, "How to record the arguments in the audit log: digest or redacted")
+	runCmd.Flags().StringVar(&options.ProfilesPath, "profiles", options.ProfilesPath, "Path to the file mapping client names to the servers and tools they can use (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringVar(&options.ToolOverridesPath, "tool-overrides", options.ToolOverridesPath, "Path to the file rewriting the names, descriptions, arguments and results of tools (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().BoolVar(&options.ValidateArguments, "validate-arguments", options.ValidateArguments, "Validate the arguments of tool calls against the tools' input schemas before forwarding them")
+	runCmd.Flags().BoolVar(&options.CoerceArguments, "coerce-arguments", options.CoerceArguments, "Convert simple arguments to the type expected by the tools' input schemas, eg. \"5\" to 5")
+	runCmd.Flags().StringVar(&options.ValidateOutput, "validate-output", options.ValidateOutput, "What to do with structured tool results that don't match the tools' output schemas: off, warn, strip or fail")
+	runCmd.Flags().BoolVar(&options.SynthesizeStructuredContent, "synthesize-structured-content", options.SynthesizeStructuredContent, "Parse the JSON text results of tools with an output schema into structured content when they don't provide any")
+	runCmd.Flags().IntVar(&options.OffloadThreshold, "offload-threshold", options.OffloadThreshold, "Size in bytes above which tool results are stored by the gateway and replaced with a link to a resource (0 to disable)")
+	runCmd.Flags().StringVar(&options.OffloadDir, "offload-dir", options.OffloadDir, "Directory where offloaded tool results are stored (in memory if empty)")
+	runCmd.Flags().DurationVar(&options.OffloadTTL, "offload-ttl", options.OffloadTTL, "How long offloaded tool results can be read")
+	runCmd.Flags().IntVar(&options.OffloadPageSize, "offload-page-size", options.OffloadPageSize, "Size in bytes of the pages in which offloaded tool results are read")
+	runCmd.Flags().IntVar(&options.OffloadMaxSize, "offload-max-size", options.OffloadMaxSize, "Maximum total size in MB of the offloaded tool results, the oldest are removed first (0 for no limit)")
+	runCmd.Flags().StringVar(&options.VirtualToolsPath, "virtual-tools", options.VirtualToolsPath, "Path to the file defining virtual tools that chain calls to other tools (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().IntVar(&options.ToolsBudget, "tools-budget", options.ToolsBudget, "Maximum number of tokens, as estimated, that the list of tools can use (0 for no limit)")
+	runCmd.Flags().StringSliceVar(&options.ToolsBudgetStrategies, "tools-budget-strategies", options.ToolsBudgetStrategies, "How to fit the tools in the budget, in this order: descriptions (shorten descriptions), schema-descriptions (drop the descriptions of optional arguments) and hide (hide the tools least called according to --audit-log)")
+	runCmd.Flags().DurationVar(&options.ToolsBudgetWindow, "tools-budget-window", options.ToolsBudgetWindow, "How far back in --audit-log to count the calls to each tool, for the hide strategy (0 for the whole log)")
+	runCmd.Flags().StringVar(&options.PolicyPath, "policy", options.PolicyPath, "Path to the tool policy file with allow and deny rules (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringVar(&options.MountPolicyPath, "mount-policy", options.MountPolicyPath, "Path to the file listing the host paths MCP Servers can mount (absolute or relative to ~/.docker/mcp/). The usual credential directories, like ~/.ssh, are always denied")
+	runCmd.Flags().BoolVar(&options.ConfirmDestructive, "confirm-destructive", options.ConfirmDestructive, "Ask the user to approve calls to tools that can be destructive, ie. not annotated as read-only or non-destructive")
+	runCmd.Flags().StringSliceVar(&options.ConfirmTools, "confirm-tools", options.ConfirmTools, "Ask the user to approve calls to tools matching those patterns (e.g. 'delete_*')")
+	runCmd.Flags().StringVar(&options.RecordDir, "record-dir", options.RecordDir, "Directory where to record the sessions with each MCP server, for later replay")
+	runCmd.Flags().StringVar(&options.ReplayDir, "replay-dir", options.ReplayDir, "Directory of recorded sessions to replay in place of the MCP servers")
 
 	// Very experimental features
 	runCmd.Flags().BoolVar(&options.Central, "central", options.Central, "In central mode, clients tell us which servers to enable")
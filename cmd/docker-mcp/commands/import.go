@@ -11,7 +11,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/oci"
 )
 
-func runMcpregistryImport(ctx context.Context, serverURL string, servers *[]catalog.Server) error {
+func runMcpregistryImport(ctx context.Context, serverURL string, source oci.Source, servers *[]catalog.Server) error {
 	// Validate URL
 	parsedURL, err := url.Parse(serverURL)
 	if err != nil {
@@ -49,7 +49,7 @@ func runMcpregistryImport(ctx context.Context, serverURL string, servers *[]cata
 	}
 
 	// Convert to catalog server
-	catalogServer := serverDetail.ToCatalogServer()
+	catalogServer := serverDetail.ToCatalogServerFrom(source)
 
 	// Add to servers slice if provided (for gateway use)
 	if servers != nil {
@@ -80,10 +80,16 @@ func runMcpregistryImport(ctx context.Context, serverURL string, servers *[]cata
 			fmt.Printf("Version: %s\n", serverDetail.VersionDetail.Version)
 		}
 
-		if len(serverDetail.Packages) > 0 {
+		if catalogServer.Remote.URL != "" {
+			fmt.Printf("Remote: %s (%s)\n", catalogServer.Remote.URL, catalogServer.Remote.Transport)
+		} else if len(serverDetail.Packages) > 0 {
 			pkg := serverDetail.Packages[0]
 			fmt.Printf("Registry Type: %s\n", pkg.RegistryType)
-			fmt.Printf("Image: %s:%s\n", pkg.Identifier, pkg.Version)
+			if catalogServer.Package != nil {
+				fmt.Printf("Package: %s %s (run with %s)\n", catalogServer.Package.Name, catalogServer.Package.Version, catalogServer.Package.Runner)
+			} else {
+				fmt.Printf("Image: %s:%s\n", pkg.Identifier, pkg.Version)
+			}
 			fmt.Printf("Environment Variables: %d\n", len(pkg.Env))
 		}
 
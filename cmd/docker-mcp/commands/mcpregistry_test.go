@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/oci"
 )
 
 func TestMcpregistryImportCommand(t *testing.T) {
@@ -69,7 +71,7 @@ func TestMcpregistryImportCommand(t *testing.T) {
 
 	// Test the import function
 	ctx := context.Background()
-	err := runMcpregistryImport(ctx, testServer.URL, nil)
+	err := runMcpregistryImport(ctx, testServer.URL, oci.SourcePackage, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
@@ -79,13 +81,13 @@ func TestMcpregistryImportCommand_InvalidURL(t *testing.T) {
 	ctx := context.Background()
 
 	// Test invalid URL
-	err := runMcpregistryImport(ctx, "not-a-url", nil)
+	err := runMcpregistryImport(ctx, "not-a-url", oci.SourcePackage, nil)
 	if err == nil {
 		t.Error("Expected error for invalid URL, got none")
 	}
 
 	// Test unsupported scheme
-	err = runMcpregistryImport(ctx, "ftp://example.com", nil)
+	err = runMcpregistryImport(ctx, "ftp://example.com", oci.SourcePackage, nil)
 	if err == nil {
 		t.Error("Expected error for unsupported scheme, got none")
 	}
@@ -99,7 +101,7 @@ func TestMcpregistryImportCommand_HTTPError(t *testing.T) {
 	defer testServer.Close()
 
 	ctx := context.Background()
-	err := runMcpregistryImport(ctx, testServer.URL, nil)
+	err := runMcpregistryImport(ctx, testServer.URL, oci.SourcePackage, nil)
 	if err == nil {
 		t.Error("Expected error for 404 response, got none")
 	}
@@ -118,7 +120,7 @@ func TestMcpregistryImportCommand_InvalidJSON(t *testing.T) {
 	defer testServer.Close()
 
 	ctx := context.Background()
-	err := runMcpregistryImport(ctx, testServer.URL, nil)
+	err := runMcpregistryImport(ctx, testServer.URL, oci.SourcePackage, nil)
 	if err == nil {
 		t.Error("Expected error for invalid JSON, got none")
 	}
@@ -1,12 +1,15 @@
 package commands
 
 import (
+	"errors"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/policy"
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/toolpolicy"
+	toolpolicies "github.com/docker/mcp-gateway/pkg/policy"
 	"github.com/docker/mcp-gateway/pkg/tui"
 )
 
@@ -21,11 +24,19 @@ docker mcp policy set "my-secret allows postgres"
 cat policy.conf | docker mcp policy set
 `
 
+const testPolicyExample = `
+### Check whether a single call is allowed
+docker mcp policy test --server filesystem --tool write_file --arguments '{"path": "/etc/passwd"}'
+
+### Check a list of sample calls
+docker mcp policy test --file ./policy.yaml --calls ./calls.yaml
+`
+
 func policyCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "policy",
 		Aliases: []string{"policies"},
-		Short:   "Manage secret policies",
+		Short:   "Manage secret policies and test tool policies",
 	}
 
 	cmd.AddCommand(&cobra.Command{
@@ -54,5 +65,63 @@ func policyCommand() *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(testPolicyCommand())
+
+	return cmd
+}
+
+func testPolicyCommand() *cobra.Command {
+	var opts struct {
+		File      string
+		Calls     string
+		Server    string
+		Tool      string
+		Client    string
+		Arguments string
+		JSON      bool
+	}
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Dry-run a tool policy against sample calls",
+		Long:  "Evaluate sample tool calls against a tool policy file, as `docker mcp gateway run --policy` would, and explain each decision.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var calls []toolpolicies.Call
+			if opts.Calls != "" {
+				var err error
+				calls, err = toolpolicy.ReadCalls(opts.Calls)
+				if err != nil {
+					return err
+				}
+			}
+			if opts.Server != "" || opts.Tool != "" {
+				arguments, err := toolpolicy.ParseArguments(opts.Arguments)
+				if err != nil {
+					return err
+				}
+				calls = append(calls, toolpolicies.Call{
+					Server:    opts.Server,
+					Tool:      opts.Tool,
+					Client:    opts.Client,
+					Arguments: arguments,
+				})
+			}
+			if len(calls) == 0 {
+				return errors.New("no call to test: use --server and --tool, or --calls")
+			}
+
+			return toolpolicy.Test(cmd.OutOrStdout(), opts.File, calls, opts.JSON)
+		},
+		Example: strings.Trim(testPolicyExample, "\n"),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.File, "file", toolpolicy.DefaultFilename, "Path to the tool policy (absolute or relative to ~/.docker/mcp/)")
+	flags.StringVar(&opts.Calls, "calls", "", "Path to a YAML or JSON file with a list of sample calls")
+	flags.StringVar(&opts.Server, "server", "", "Server of the sample call")
+	flags.StringVar(&opts.Tool, "tool", "", "Tool of the sample call")
+	flags.StringVar(&opts.Client, "client", "", "Name of the client making the sample call")
+	flags.StringVar(&opts.Arguments, "arguments", "", "Arguments of the sample call, as a JSON object")
+	flags.BoolVar(&opts.JSON, "json", false, "Print the decisions as JSON")
+
 	return cmd
 }
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/prompts"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func promptsCommand(docker docker.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompts",
+		Short: "Manage prompts",
+	}
+
+	var (
+		version     string
+		verbose     bool
+		format      string
+		gatewayArgs []string
+	)
+	cmd.PersistentFlags().StringVar(&version, "version", "2", "Version of the gateway")
+	cmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output")
+	cmd.PersistentFlags().StringVar(&format, "format", "list", "Output format (json|list)")
+	cmd.PersistentFlags().StringSliceVar(&gatewayArgs, "gateway-arg", nil, "Additional arguments passed to the gateway")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List prompts",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return prompts.List(cmd.Context(), version, gatewayArgs, verbose, format)
+		},
+	})
+
+	var enableServerName string
+	enableCmd := &cobra.Command{
+		Use:   "enable [prompt1] [prompt2] ...",
+		Short: "enable one or more prompts",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return prompts.Enable(cmd.Context(), docker, args, enableServerName)
+		},
+	}
+	enableCmd.Flags().StringVar(&enableServerName, "server", "", "Specify which server provides the prompts")
+	_ = enableCmd.MarkFlagRequired("server")
+	cmd.AddCommand(enableCmd)
+
+	var disableServerName string
+	disableCmd := &cobra.Command{
+		Use:   "disable [prompt1] [prompt2] ...",
+		Short: "disable one or more prompts",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return prompts.Disable(cmd.Context(), docker, version, gatewayArgs, verbose, args, disableServerName)
+		},
+	}
+	disableCmd.Flags().StringVar(&disableServerName, "server", "", "Specify which server provides the prompts")
+	_ = disableCmd.MarkFlagRequired("server")
+	cmd.AddCommand(disableCmd)
+
+	return cmd
+}
@@ -25,6 +25,7 @@ func registryCommand() *cobra.Command {
 
 func registryConvertCommand() *cobra.Command {
 	var filePath string
+	var prefer string
 
 	cmd := &cobra.Command{
 		Use:   "convert",
@@ -33,6 +34,10 @@ func registryConvertCommand() *cobra.Command {
 			if filePath == "" {
 				return fmt.Errorf("--file flag is required")
 			}
+			source, err := oci.ParseSource(prefer)
+			if err != nil {
+				return err
+			}
 
 			// Read the file contents
 			fileContents, err := os.ReadFile(filePath)
@@ -48,7 +53,7 @@ func registryConvertCommand() *cobra.Command {
 			}
 
 			// Convert to catalog server
-			catalogServer := serverDetail.ToCatalogServer()
+			catalogServer := serverDetail.ToCatalogServerFrom(source)
 
 			// Marshal to YAML and print to stdout
 			outputYAML, err := yaml.Marshal(catalogServer)
@@ -62,6 +67,7 @@ func registryConvertCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to the OCI registry server definition JSON file")
+	cmd.Flags().StringVar(&prefer, "prefer", string(oci.SourceRemote), "What to convert when the server is published both as a package and as a remote: package or remote")
 	if err := cmd.MarkFlagRequired("file"); err != nil {
 		// This should not happen in practice, but we need to handle the error for linting
 		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/resources"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func resourcesCommand(docker docker.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources",
+		Short: "Manage resources and resource templates",
+	}
+
+	var (
+		version     string
+		verbose     bool
+		format      string
+		gatewayArgs []string
+	)
+	cmd.PersistentFlags().StringVar(&version, "version", "2", "Version of the gateway")
+	cmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output")
+	cmd.PersistentFlags().StringVar(&format, "format", "list", "Output format (json|list)")
+	cmd.PersistentFlags().StringSliceVar(&gatewayArgs, "gateway-arg", nil, "Additional arguments passed to the gateway")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List resources and resource templates",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return resources.List(cmd.Context(), version, gatewayArgs, verbose, format)
+		},
+	})
+
+	var enableServerName string
+	enableCmd := &cobra.Command{
+		Use:   "enable [uri1] [uri2] ...",
+		Short: "enable one or more resources or resource templates, by URI or name",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resources.Enable(cmd.Context(), docker, args, enableServerName)
+		},
+	}
+	enableCmd.Flags().StringVar(&enableServerName, "server", "", "Specify which server provides the resources")
+	_ = enableCmd.MarkFlagRequired("server")
+	cmd.AddCommand(enableCmd)
+
+	var disableServerName string
+	disableCmd := &cobra.Command{
+		Use:   "disable [uri1] [uri2] ...",
+		Short: "disable one or more resources or resource templates, by URI or name",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resources.Disable(cmd.Context(), docker, version, gatewayArgs, verbose, args, disableServerName)
+		},
+	}
+	disableCmd.Flags().StringVar(&disableServerName, "server", "", "Specify which server provides the resources")
+	_ = disableCmd.MarkFlagRequired("server")
+	cmd.AddCommand(disableCmd)
+
+	return cmd
+}
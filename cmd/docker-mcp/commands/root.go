@@ -70,6 +70,7 @@ func Root(ctx context.Context, cwd string, dockerCli command.Cli) *cobra.Command
 
 	dockerClient := docker.NewClient(dockerCli)
 
+	cmd.AddCommand(auditCommand())
 	cmd.AddCommand(catalogCommand())
 	cmd.AddCommand(clientCommand(cwd))
 	cmd.AddCommand(configCommand(dockerClient))
@@ -77,7 +78,9 @@ func Root(ctx context.Context, cwd string, dockerCli command.Cli) *cobra.Command
 	cmd.AddCommand(gatewayCommand(dockerClient, dockerCli))
 	cmd.AddCommand(oauthCommand())
 	cmd.AddCommand(policyCommand())
+	cmd.AddCommand(promptsCommand(dockerClient))
 	cmd.AddCommand(registryCommand())
+	cmd.AddCommand(resourcesCommand(dockerClient))
 	cmd.AddCommand(secretCommand(dockerClient))
 	cmd.AddCommand(serverCommand(dockerClient, dockerCli))
 	cmd.AddCommand(toolsCommand(dockerClient))
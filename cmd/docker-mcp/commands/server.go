@@ -73,7 +73,8 @@ func serverCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 		},
 	})
 
-	cmd.AddCommand(&cobra.Command{
+	var seccompProfile string
+	inspectCommand := &cobra.Command{
 		Use:   "inspect",
 		Short: "Get information about a server or inspect an OCI artifact",
 		Args:  cobra.ExactArgs(1),
@@ -88,7 +89,7 @@ func serverCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 			}
 
 			// Use regular server inspect for server names
-			info, err := server.Inspect(cmd.Context(), docker, arg)
+			info, err := server.Inspect(cmd.Context(), docker, arg, seccompProfile)
 			if err != nil {
 				return err
 			}
@@ -101,7 +102,9 @@ func serverCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 			_, _ = cmd.OutOrStdout().Write(buf)
 			return nil
 		},
-	})
+	}
+	inspectCommand.Flags().StringVar(&seccompProfile, "seccomp-profile", "", "Path to the custom seccomp profile the gateway runs the MCP Servers with")
+	cmd.AddCommand(inspectCommand)
 
 	cmd.AddCommand(&cobra.Command{
 		Use:   "reset",
@@ -43,6 +43,15 @@ func toolsCommand(docker docker.Client) *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "budget",
+		Short: "Estimate how many tokens the tools use, per server and per tool",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return tools.Budget(cmd.Context(), version, gatewayArgs, verbose, format)
+		},
+	})
+
 	cmd.AddCommand(&cobra.Command{
 		Use:   "inspect",
 		Short: "Inspect a tool",
@@ -0,0 +1,45 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/tools"
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func Enable(ctx context.Context, docker docker.Client, promptNames []string, serverName string) error {
+	return config.EnableFiltered(ctx, docker, "prompts.yaml", serverName, promptNames)
+}
+
+func Disable(ctx context.Context, docker docker.Client, version string, gatewayArgs []string, debug bool, promptNames []string, serverName string) error {
+	return config.DisableFiltered(ctx, docker, "prompts.yaml", serverName, promptNames, func() ([][]string, error) {
+		return serverPrompts(ctx, version, gatewayArgs, debug, serverName)
+	})
+}
+
+// serverPrompts lists the prompts of a server, through a gateway that runs
+// only that server.
+func serverPrompts(ctx context.Context, version string, gatewayArgs []string, debug bool, serverName string) ([][]string, error) {
+	c, err := tools.Start(ctx, version, append(slices.Clone(gatewayArgs), "--servers="+serverName), debug)
+	if err != nil {
+		return nil, fmt.Errorf("starting client: %w", err)
+	}
+	defer c.Close()
+
+	response, err := c.ListPrompts(ctx, &mcp.ListPromptsParams{})
+	if err != nil {
+		return nil, fmt.Errorf("listing prompts: %w", err)
+	}
+
+	var prompts [][]string
+	for _, prompt := range response.Prompts {
+		prompts = append(prompts, []string{prompt.Name})
+	}
+
+	return prompts, nil
+}
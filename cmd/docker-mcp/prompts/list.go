@@ -0,0 +1,41 @@
+package prompts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/tools"
+)
+
+func List(ctx context.Context, version string, gatewayArgs []string, debug bool, format string) error {
+	c, err := tools.Start(ctx, version, gatewayArgs, debug)
+	if err != nil {
+		return fmt.Errorf("starting client: %w", err)
+	}
+	defer c.Close()
+
+	response, err := c.ListPrompts(ctx, &mcp.ListPromptsParams{})
+	if err != nil {
+		return fmt.Errorf("listing prompts: %w", err)
+	}
+
+	if format == "json" {
+		buf, err := json.MarshalIndent(response.Prompts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling prompts: %w", err)
+		}
+
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	fmt.Println(len(response.Prompts), "prompts:")
+	for _, prompt := range response.Prompts {
+		fmt.Println(" -", prompt.Name, "-", prompt.Description)
+	}
+
+	return nil
+}
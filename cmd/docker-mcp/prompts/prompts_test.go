@@ -0,0 +1,123 @@
+package prompts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func TestDisablePromptUnlisted(t *testing.T) {
+	ctx, docker := setup(t, "")
+
+	err := config.UpdateFilterConfig(ctx, docker, "prompts.yaml", "github", func(promptsConfig config.FilterConfig) error {
+		return promptsConfig.Disable("github", []string{"summarize"}, func() ([][]string, error) {
+			return [][]string{{"summarize"}, {"review"}, {"triage"}}, nil
+		})
+	})
+	require.NoError(t, err)
+
+	promptsConfig := readPromptsConfig(t, ctx, docker)
+	assert.Equal(t, []string{"review", "triage"}, promptsConfig.Servers["github"])
+	assert.True(t, promptsConfig.IsEnabled("github", "review"))
+	assert.False(t, promptsConfig.IsEnabled("github", "summarize"))
+	assert.True(t, promptsConfig.IsEnabled("other", "summarize"))
+}
+
+func TestDisablePromptListed(t *testing.T) {
+	ctx, docker := setup(t, "github:\n  - review\n  - summarize\n")
+
+	// The server is listed and so is the prompt: no need to start a gateway.
+	err := Disable(ctx, docker, "2", nil, false, []string{"summarize"}, "github")
+	require.NoError(t, err)
+
+	promptsConfig := readPromptsConfig(t, ctx, docker)
+	assert.Equal(t, []string{"review"}, promptsConfig.Servers["github"])
+}
+
+func TestDisableLastPrompt(t *testing.T) {
+	ctx, docker := setup(t, "github:\n  - review\n")
+
+	err := Disable(ctx, docker, "2", nil, false, []string{"review"}, "github")
+	require.NoError(t, err)
+
+	promptsConfig := readPromptsConfig(t, ctx, docker)
+	assert.Equal(t, []string{}, promptsConfig.Servers["github"])
+	assert.False(t, promptsConfig.IsEnabled("github", "review"))
+}
+
+func TestEnablePromptListed(t *testing.T) {
+	ctx, docker := setup(t, "github:\n  - review\n")
+
+	err := Enable(ctx, docker, []string{"summarize"}, "github")
+	require.NoError(t, err)
+
+	promptsConfig := readPromptsConfig(t, ctx, docker)
+	assert.Equal(t, []string{"review", "summarize"}, promptsConfig.Servers["github"])
+	assert.False(t, promptsConfig.IsEnabled("github", "triage"))
+}
+
+func TestEnablePromptUnlisted(t *testing.T) {
+	ctx, docker := setup(t, "")
+
+	err := Enable(ctx, docker, []string{"summarize"}, "github")
+	require.NoError(t, err)
+
+	promptsConfig := readPromptsConfig(t, ctx, docker)
+	assert.NotContains(t, promptsConfig.Servers, "github")
+	assert.True(t, promptsConfig.IsEnabled("github", "review"))
+}
+
+func TestUpdatePromptsWithoutServer(t *testing.T) {
+	ctx, docker := setup(t, "")
+
+	err := Enable(ctx, docker, []string{"summarize"}, "")
+	require.ErrorContains(t, err, "a server name is required")
+}
+
+func TestEnablePromptUnknownServer(t *testing.T) {
+	ctx, docker := setup(t, "")
+
+	err := Enable(ctx, docker, []string{"summarize"}, "gihtub")
+	require.ErrorContains(t, err, "server gihtub is not enabled")
+
+	err = Disable(ctx, docker, "2", nil, false, []string{"summarize"}, "gihtub")
+	require.ErrorContains(t, err, "server gihtub is not enabled")
+}
+
+func readPromptsConfig(t *testing.T, ctx context.Context, docker docker.Client) config.FilterConfig {
+	t.Helper()
+
+	promptsYAML, err := config.ReadPrompts(ctx, docker)
+	require.NoError(t, err)
+	promptsConfig, err := config.ParseFilterConfig(promptsYAML)
+	require.NoError(t, err)
+
+	return promptsConfig
+}
+
+func setup(t *testing.T, promptsYAML string) (context.Context, docker.Client) {
+	t.Helper()
+
+	home := t.TempDir()
+	if runtime.GOOS == "windows" {
+		t.Setenv("USERPROFILE", home)
+	} else {
+		t.Setenv("HOME", home)
+	}
+
+	path := filepath.Join(home, ".docker/mcp/prompts.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(promptsYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".docker/mcp/registry.yaml"), []byte("registry:\n  github:\n    ref: \"\"\n"), 0o644))
+
+	// The prompts and registry files exist so the legacy docker volume is never read.
+	return t.Context(), nil
+}
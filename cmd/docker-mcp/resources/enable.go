@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/tools"
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func Enable(ctx context.Context, docker docker.Client, resourceNames []string, serverName string) error {
+	return config.EnableFiltered(ctx, docker, "resources.yaml", serverName, resourceNames)
+}
+
+func Disable(ctx context.Context, docker docker.Client, version string, gatewayArgs []string, debug bool, resourceNames []string, serverName string) error {
+	return config.DisableFiltered(ctx, docker, "resources.yaml", serverName, resourceNames, func() ([][]string, error) {
+		return serverResources(ctx, version, gatewayArgs, debug, serverName)
+	})
+}
+
+// serverResources lists the resources and resource templates of a server,
+// through a gateway that runs only that server. They are listed by URI and
+// can be disabled by URI or by name.
+func serverResources(ctx context.Context, version string, gatewayArgs []string, debug bool, serverName string) ([][]string, error) {
+	c, err := tools.Start(ctx, version, append(slices.Clone(gatewayArgs), "--servers="+serverName), debug)
+	if err != nil {
+		return nil, fmt.Errorf("starting client: %w", err)
+	}
+	defer c.Close()
+
+	resources, err := c.ListResources(ctx, &mcp.ListResourcesParams{})
+	if err != nil {
+		return nil, fmt.Errorf("listing resources: %w", err)
+	}
+
+	resourceTemplates, err := c.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
+	if err != nil {
+		return nil, fmt.Errorf("listing resource templates: %w", err)
+	}
+
+	var all [][]string
+	for _, resource := range resources.Resources {
+		all = append(all, []string{resource.URI, resource.Name})
+	}
+	for _, resourceTemplate := range resourceTemplates.ResourceTemplates {
+		all = append(all, []string{resourceTemplate.URITemplate, resourceTemplate.Name})
+	}
+
+	return all, nil
+}
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/tools"
+)
+
+func List(ctx context.Context, version string, gatewayArgs []string, debug bool, format string) error {
+	c, err := tools.Start(ctx, version, gatewayArgs, debug)
+	if err != nil {
+		return fmt.Errorf("starting client: %w", err)
+	}
+	defer c.Close()
+
+	resources, err := c.ListResources(ctx, &mcp.ListResourcesParams{})
+	if err != nil {
+		return fmt.Errorf("listing resources: %w", err)
+	}
+
+	resourceTemplates, err := c.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
+	if err != nil {
+		return fmt.Errorf("listing resource templates: %w", err)
+	}
+
+	if format == "json" {
+		buf, err := json.MarshalIndent(map[string]any{
+			"resources":         resources.Resources,
+			"resourceTemplates": resourceTemplates.ResourceTemplates,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling resources: %w", err)
+		}
+
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	fmt.Println(len(resources.Resources), "resources:")
+	for _, resource := range resources.Resources {
+		fmt.Println(" -", resource.URI, "-", resource.Name)
+	}
+	fmt.Println(len(resourceTemplates.ResourceTemplates), "resource templates:")
+	for _, resourceTemplate := range resourceTemplates.ResourceTemplates {
+		fmt.Println(" -", resourceTemplate.URITemplate, "-", resourceTemplate.Name)
+	}
+
+	return nil
+}
@@ -12,13 +12,18 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
+	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/hardening"
 )
 
 type Info struct {
-	Tools  []Tool `json:"tools"`
-	Readme string `json:"readme"`
+	Tools           []Tool             `json:"tools"`
+	Readme          string             `json:"readme"`
+	ProtocolVersion string             `json:"protocolVersion,omitempty"`
+	Security        *hardening.Profile `json:"security,omitempty"`
 }
 
 func (s Info) ToJSON() ([]byte, error) {
@@ -39,7 +44,7 @@ type Tool struct {
 	Enabled     bool                       `json:"enabled"`
 }
 
-func Inspect(ctx context.Context, dockerClient docker.Client, serverName string) (Info, error) {
+func Inspect(ctx context.Context, dockerClient docker.Client, serverName string, seccompProfile string) (Info, error) {
 	catalogYAML, err := catalog.ReadCatalogFile(catalog.DockerCatalogName)
 	if err != nil {
 		return Info{}, err
@@ -106,12 +111,45 @@ func Inspect(ctx context.Context, dockerClient docker.Client, serverName string)
 		return Info{}, err
 	}
 
+	// Protocol version negotiated the last time the gateway connected to this server.
+	var protocolVersion string
+	if serversInfo, err := config.ReadServersInfo(); err == nil {
+		protocolVersion = serversInfo[serverName].ProtocolVersion
+	}
+
+	security, err := securityProfile(ctx, dockerClient, catalogYAML, serverName, seccompProfile)
+	if err != nil {
+		return Info{}, err
+	}
+
 	return Info{
-		Tools:  tools,
-		Readme: string(readmeRaw),
+		Tools:           tools,
+		Readme:          string(readmeRaw),
+		ProtocolVersion: protocolVersion,
+		Security:        security,
 	}, nil
 }
 
+// securityProfile is the hardened profile the gateway runs a server with,
+// given the seccomp profile the gateway is configured with. Remote servers
+// don't have one.
+func securityProfile(ctx context.Context, dockerClient docker.Client, catalogYAML []byte, serverName string, seccompProfile string) (*hardening.Profile, error) {
+	var servers struct {
+		Registry map[string]catalogTypes.Server `yaml:"registry"`
+	}
+	if err := yaml.Unmarshal(catalogYAML, &servers); err != nil {
+		return nil, err
+	}
+
+	server := servers.Registry[serverName]
+	if server.Image == "" && server.Package == nil {
+		return nil, nil
+	}
+
+	profile := gateway.SecurityProfile(ctx, dockerClient, server, seccompProfile)
+	return &profile, nil
+}
+
 // TODO: Should we get all those directly with the catalog?
 func fetch(ctx context.Context, url string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
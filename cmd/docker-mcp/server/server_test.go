@@ -10,13 +10,17 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/hardening"
 )
 
 func TestListVolumeNotFound(t *testing.T) {
@@ -65,6 +69,69 @@ func TestList(t *testing.T) {
 	assert.Equal(t, []string{"git"}, enabled)
 }
 
+func TestSecurityProfile(t *testing.T) {
+	catalogYAML := []byte(`registry:
+  hardened:
+    image: mcp/hardened
+  relaxed:
+    image: mcp/relaxed
+    security:
+      writableRootfs: true
+      keepCapabilities: true
+  remote:
+    remote:
+      url: https://example.com/mcp
+`)
+	docker := &fakeDocker{imageUser: "node"}
+
+	profile, err := securityProfile(t.Context(), docker, catalogYAML, "hardened", "")
+	require.NoError(t, err)
+	assert.Equal(t, &hardening.Profile{
+		NoNewPrivileges: true,
+		ReadOnlyRootFS:  true,
+		Tmpfs:           map[string]string{"/tmp": "rw,nosuid,nodev,size=64m"},
+		CapDrop:         []string{"ALL"},
+		PidsLimit:       hardening.PidsLimit,
+	}, profile)
+
+	profile, err = securityProfile(t.Context(), docker, catalogYAML, "relaxed", "")
+	require.NoError(t, err)
+	assert.False(t, profile.ReadOnlyRootFS)
+	assert.Empty(t, profile.CapDrop)
+
+	profile, err = securityProfile(t.Context(), docker, catalogYAML, "remote", "")
+	require.NoError(t, err)
+	assert.Nil(t, profile)
+}
+
+func TestSecurityProfileLikeGateway(t *testing.T) {
+	catalogYAML := []byte(`registry:
+  image:
+    image: mcp/image
+    security:
+      runAsNonRoot: true
+  package:
+    security:
+      runAsNonRoot: true
+    package:
+      runner: npx
+      name: "@modelcontextprotocol/server-everything"
+`)
+	docker := &fakeDocker{}
+
+	profile, err := securityProfile(t.Context(), docker, catalogYAML, "image", "/etc/seccomp.json")
+	require.NoError(t, err)
+	assert.Equal(t, hardening.NonRootUser, profile.User)
+	assert.Equal(t, "/etc/seccomp.json", profile.Seccomp)
+
+	// Package servers run as root, to write to their package cache.
+	profile, err = securityProfile(t.Context(), docker, catalogYAML, "package", "/etc/seccomp.json")
+	require.NoError(t, err)
+	assert.Empty(t, profile.User)
+	assert.True(t, profile.ReadOnlyRootFS)
+	assert.Equal(t, "/etc/seccomp.json", profile.Seccomp)
+}
+
 func TestEnableNotFound(t *testing.T) {
 	ctx, _, docker := setup(t, withEmptyRegistryYaml(), withEmptyCatalog())
 
@@ -162,12 +229,19 @@ type fakeDocker struct {
 	docker.Client
 	volume     volume.Volume
 	inspectErr error
+	imageUser  string
 }
 
 func (f *fakeDocker) InspectVolume(context.Context, string) (volume.Volume, error) {
 	return f.volume, f.inspectErr
 }
 
+func (f *fakeDocker) InspectImage(context.Context, string) (image.InspectResponse, error) {
+	return image.InspectResponse{Config: &dockerspec.DockerOCIImageConfig{
+		ImageConfig: ocispec.ImageConfig{User: f.imageUser},
+	}}, nil
+}
+
 type exitCodeErr struct {
 	exitCode int
 }
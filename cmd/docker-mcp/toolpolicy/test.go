@@ -0,0 +1,90 @@
+package toolpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+const DefaultFilename = "policy.yaml"
+
+type result struct {
+	policy.Call
+	Allowed     bool   `json:"allowed"`
+	Explanation string `json:"explanation"`
+}
+
+// Test evaluates sample calls against a policy file without running the gateway.
+func Test(out io.Writer, file string, calls []policy.Call, outputJSON bool) error {
+	path, err := config.FilePath(file)
+	if err != nil {
+		return err
+	}
+
+	toolPolicy, err := policy.Read(path)
+	if err != nil {
+		return err
+	}
+
+	results := make([]result, 0, len(calls))
+	for _, call := range calls {
+		decision := toolPolicy.Evaluate(call)
+		results = append(results, result{
+			Call:        call,
+			Allowed:     decision.Allowed,
+			Explanation: decision.Explanation,
+		})
+	}
+
+	if outputJSON {
+		jsonData, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(jsonData))
+		return nil
+	}
+
+	for _, result := range results {
+		verdict := "ALLOW"
+		if !result.Allowed {
+			verdict = "DENY"
+		}
+		fmt.Fprintf(out, "%-5s %s/%s: %s\n", verdict, result.Server, result.Tool, result.Explanation)
+	}
+	return nil
+}
+
+// ReadCalls reads a list of sample calls from a YAML or JSON file.
+func ReadCalls(file string) ([]policy.Call, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []policy.Call
+	if err := yaml.Unmarshal(buf, &calls); err != nil {
+		return nil, fmt.Errorf("parsing calls %s: %w", file, err)
+	}
+
+	return calls, nil
+}
+
+// ParseArguments parses the arguments of a sample call given as a JSON object.
+func ParseArguments(value string) (map[string]any, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(value), &arguments); err != nil {
+		return nil, fmt.Errorf("invalid arguments, expected a JSON object: %w", err)
+	}
+	return arguments, nil
+}
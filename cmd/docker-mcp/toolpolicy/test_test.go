@@ -0,0 +1,79 @@
+package toolpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+const testPolicy = `
+rules:
+  - name: no-drop
+    action: deny
+    server: postgres
+    arguments:
+      query:
+        contains: DROP
+    reason: dropping tables is not allowed
+`
+
+func TestTest(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testPolicy), 0o644))
+
+	calls := []policy.Call{
+		{Server: "postgres", Tool: "query", Arguments: map[string]any{"query": "drop table users"}},
+		{Server: "postgres", Tool: "query", Arguments: map[string]any{"query": "select 1"}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, Test(&out, file, calls, false))
+	assert.Equal(t, `DENY  postgres/query: denied by rule "no-drop": dropping tables is not allowed
+ALLOW postgres/query: allowed by default: no rule matches
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, Test(&out, file, calls, true))
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "postgres", results[0]["server"])
+	assert.Equal(t, false, results[0]["allowed"])
+	assert.Equal(t, true, results[1]["allowed"])
+}
+
+func TestReadCalls(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "calls.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+- server: filesystem
+  tool: read_file
+  client: claude-desktop
+  arguments:
+    path: /workspace/README.md
+`), 0o644))
+
+	calls, err := ReadCalls(file)
+	require.NoError(t, err)
+	assert.Equal(t, []policy.Call{{
+		Server:    "filesystem",
+		Tool:      "read_file",
+		Client:    "claude-desktop",
+		Arguments: map[string]any{"path": "/workspace/README.md"},
+	}}, calls)
+}
+
+func TestParseArguments(t *testing.T) {
+	arguments, err := ParseArguments(`{"path": "/tmp"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"path": "/tmp"}, arguments)
+
+	_, err = ParseArguments(`[1]`)
+	require.Error(t, err)
+}
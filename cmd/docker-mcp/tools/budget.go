@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/budget"
+)
+
+const unknownServer = "unknown"
+
+type budgetReport struct {
+	Total   int            `json:"total"`
+	Servers []serverBudget `json:"servers"`
+}
+
+type serverBudget struct {
+	Name   string       `json:"name"`
+	Tokens int          `json:"tokens"`
+	Tools  []toolBudget `json:"tools"`
+}
+
+type toolBudget struct {
+	Name   string `json:"name"`
+	Tokens int    `json:"tokens"`
+}
+
+// Budget reports how many tokens, as estimated, the tools of each server use in tools/list.
+func Budget(ctx context.Context, version string, gatewayArgs []string, debug bool, format string) error {
+	c, err := Start(ctx, version, gatewayArgs, debug)
+	if err != nil {
+		return fmt.Errorf("starting client: %w", err)
+	}
+	defer c.Close()
+
+	response, err := c.ListTools(ctx, &mcp.ListToolsParams{})
+	if err != nil {
+		return fmt.Errorf("listing tools: %w", err)
+	}
+
+	report := newBudgetReport(response.Tools)
+	if format == "json" {
+		buf, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling budget: %w", err)
+		}
+
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	printBudgetReport(os.Stdout, report, len(response.Tools))
+	return nil
+}
+
+func newBudgetReport(tools []*mcp.Tool) budgetReport {
+	byServer := map[string]*serverBudget{}
+
+	report := budgetReport{}
+	for _, tool := range tools {
+		tokens := budget.Estimate(tool)
+		report.Total += tokens
+
+		serverName := serverOfTool(tool)
+		server, found := byServer[serverName]
+		if !found {
+			server = &serverBudget{Name: serverName}
+			byServer[serverName] = server
+		}
+		server.Tokens += tokens
+		server.Tools = append(server.Tools, toolBudget{Name: tool.Name, Tokens: tokens})
+	}
+
+	for _, server := range byServer {
+		sort.SliceStable(server.Tools, func(i, j int) bool {
+			return server.Tools[i].Tokens > server.Tools[j].Tokens
+		})
+		report.Servers = append(report.Servers, *server)
+	}
+	sort.Slice(report.Servers, func(i, j int) bool {
+		if report.Servers[i].Tokens != report.Servers[j].Tokens {
+			return report.Servers[i].Tokens > report.Servers[j].Tokens
+		}
+		return report.Servers[i].Name < report.Servers[j].Name
+	})
+
+	return report
+}
+
+// serverOfTool reads, in its _meta, which server provides a tool. Tools that
+// don't come from a server, like virtual tools, are reported as unknown.
+func serverOfTool(tool *mcp.Tool) string {
+	if serverName, ok := tool.Meta[budget.ServerMetaKey].(string); ok && serverName != "" {
+		return serverName
+	}
+	return unknownServer
+}
+
+func printBudgetReport(out io.Writer, report budgetReport, toolCount int) {
+	fmt.Fprintf(out, "%d tools use about %d tokens\n", toolCount, report.Total)
+	for _, server := range report.Servers {
+		fmt.Fprintf(out, "\n%s: %d tokens (%d tools)\n", server.Name, server.Tokens, len(server.Tools))
+		for _, tool := range server.Tools {
+			fmt.Fprintf(out, " - %s: %d tokens\n", tool.Name, tool.Tokens)
+		}
+	}
+}
@@ -28,7 +28,7 @@ func Call(ctx context.Context, version string, gatewayArgs []string, debug bool,
 		metric.WithDescription("Tool call duration from CLI"),
 		metric.WithUnit("ms"))
 
-	c, err := start(ctx, version, gatewayArgs, debug)
+	c, err := Start(ctx, version, gatewayArgs, debug)
 	if err != nil {
 		return fmt.Errorf("starting client: %w", err)
 	}
@@ -19,7 +19,7 @@ func List(ctx context.Context, version string, gatewayArgs []string, debug bool,
 		metric.WithDescription("Number of tools discovered by CLI"),
 		metric.WithUnit("1"))
 
-	c, err := start(ctx, version, gatewayArgs, debug)
+	c, err := Start(ctx, version, gatewayArgs, debug)
 	if err != nil {
 		return fmt.Errorf("starting client: %w", err)
 	}
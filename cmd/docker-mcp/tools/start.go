@@ -10,7 +10,8 @@ import (
 	"github.com/docker/mcp-gateway/pkg/logs"
 )
 
-func start(ctx context.Context, version string, gatewayArgs []string, verbose bool) (*mcp.ClientSession, error) {
+// Start runs a gateway and connects to it as an MCP client.
+func Start(ctx context.Context, version string, gatewayArgs []string, verbose bool) (*mcp.ClientSession, error) {
 	var args []string
 	if version == "2" {
 		if verbose {
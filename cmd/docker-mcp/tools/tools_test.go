@@ -1,10 +1,13 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/api/types/volume"
@@ -12,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/mcp-gateway/pkg/budget"
 	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
 )
@@ -373,3 +377,28 @@ func TestDescriptionSummary(t *testing.T) {
 	result = descriptionSummary("Tool description.\nError Responses:\n- 404 if not found")
 	assert.Equal(t, "Tool description.", result)
 }
+
+// Unit tests for budget
+
+func TestBudgetReport(t *testing.T) {
+	tools := []*mcp.Tool{
+		{Name: "search", Description: "Search DuckDuckGo", Meta: mcp.Meta{budget.ServerMetaKey: "duckduckgo"}},
+		{Name: "fetch_content", Description: strings.Repeat("Fetch a page. ", 20), Meta: mcp.Meta{budget.ServerMetaKey: "duckduckgo"}},
+		{Name: "gh_get_issue", Description: "Get an issue", Meta: mcp.Meta{budget.ServerMetaKey: "github"}},
+		{Name: "triage", Description: "A virtual tool"},
+	}
+
+	report := newBudgetReport(tools)
+
+	require.Len(t, report.Servers, 3)
+	assert.Equal(t, "duckduckgo", report.Servers[0].Name)
+	assert.Equal(t, "fetch_content", report.Servers[0].Tools[0].Name)
+	assert.Equal(t, report.Servers[0].Tools[0].Tokens+report.Servers[0].Tools[1].Tokens, report.Servers[0].Tokens)
+	assert.ElementsMatch(t, []string{"github", "unknown"}, []string{report.Servers[1].Name, report.Servers[2].Name})
+	assert.Equal(t, report.Servers[0].Tokens+report.Servers[1].Tokens+report.Servers[2].Tokens, report.Total)
+
+	var out bytes.Buffer
+	printBudgetReport(&out, report, len(tools))
+	assert.Contains(t, out.String(), fmt.Sprintf("4 tools use about %d tokens", report.Total))
+	assert.Contains(t, out.String(), fmt.Sprintf("duckduckgo: %d tokens (2 tools)", report.Servers[0].Tokens))
+}
@@ -0,0 +1,363 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/secretsscan"
+)
+
+const (
+	OperationToolCall     = "tools/call"
+	OperationPromptGet    = "prompts/get"
+	OperationResourceRead = "resources/read"
+
+	StatusSuccess = "success"
+	StatusError   = "error"   // The tool returned a result with isError set
+	StatusFailure = "failure" // The call couldn't be completed
+
+	ArgumentsDigest   = "digest"
+	ArgumentsRedacted = "redacted"
+
+	redactedValue = "[REDACTED]"
+)
+
+// Record is one line of the audit log.
+type Record struct {
+	Timestamp       time.Time      `json:"timestamp"`
+	Operation       string         `json:"operation"`
+	Client          string         `json:"client,omitempty"`
+	ClientVersion   string         `json:"clientVersion,omitempty"`
+	Session         string         `json:"session,omitempty"`
+	Server          string         `json:"server,omitempty"`
+	Tool            string         `json:"tool,omitempty"`
+	Prompt          string         `json:"prompt,omitempty"`
+	Resource        string         `json:"resource,omitempty"`
+	ArgumentsDigest string         `json:"argumentsDigest,omitempty"`
+	Arguments       map[string]any `json:"arguments,omitempty"`
+	Status          string         `json:"status"`
+	Error           string         `json:"error,omitempty"`
+	DurationMs      int64          `json:"durationMs"`
+	RequestBytes    int            `json:"requestBytes"`
+	ResponseBytes   int            `json:"responseBytes"`
+}
+
+// Logger appends records to a JSONL file and rotates it when it grows bigger than maxSize.
+type Logger struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// Open opens (or creates) the audit log at path. A maxSize <= 0 disables rotation.
+func Open(path string, maxSize int64, maxBackups int) (*Logger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &Logger{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening audit log %s: %w", l.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("reading audit log %s: %w", l.path, err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// Log appends a record to the audit log.
+func (l *Logger) Log(record Record) error {
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log %s is closed", l.path)
+	}
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(buf)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(buf)
+	l.size += int64(n)
+	return err
+}
+
+// rotate renames audit.jsonl to audit.jsonl.1, audit.jsonl.1 to audit.jsonl.2...
+// and drops the oldest backup.
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+
+	if l.maxBackups <= 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return l.open()
+	}
+
+	_ = os.Remove(backupPath(l.path, l.maxBackups))
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(l.path, i), backupPath(l.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, backupPath(l.path, 1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return l.open()
+}
+
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
+
+// Digest returns a stable digest of the arguments of a call.
+func Digest(arguments any) string {
+	// Round-trip through a generic value so that the keys are sorted.
+	buf, err := json.Marshal(arguments)
+	if err != nil {
+		return ""
+	}
+	var generic any
+	if err := json.Unmarshal(buf, &generic); err != nil || generic == nil {
+		return ""
+	}
+	if buf, err = json.Marshal(generic); err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(buf)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Redact returns a copy of the arguments where sensitive values are replaced.
+// A value is considered sensitive if its key looks like a credential or if it contains a secret.
+func Redact(arguments map[string]any) map[string]any {
+	if arguments == nil {
+		return nil
+	}
+
+	redacted := make(map[string]any, len(arguments))
+	for key, value := range arguments {
+		redacted[key] = redactValue(key, value)
+	}
+	return redacted
+}
+
+func redactValue(key string, value any) any {
+	if isSensitiveKey(key) {
+		return redactedValue
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return Redact(v)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = redactValue("", item)
+		}
+		return items
+	case string:
+		if secretsscan.ContainsSecrets(v) {
+			return redactedValue
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range []string{"password", "passwd", "secret", "token", "apikey", "api_key", "api-key", "authorization", "credential", "private_key"} {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter selects records when querying the audit log. Empty fields match everything.
+// Server, Tool, Prompt and Resource support glob patterns.
+type Filter struct {
+	Operation string
+	Client    string
+	Session   string
+	Server    string
+	Tool      string
+	Prompt    string
+	Resource  string
+	Status    string
+	Since     time.Time
+	Until     time.Time
+}
+
+func (f Filter) Match(record Record) bool {
+	if !f.Since.IsZero() && record.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Timestamp.After(f.Until) {
+		return false
+	}
+
+	return matchExact(f.Operation, record.Operation) &&
+		matchExact(f.Client, record.Client) &&
+		matchExact(f.Session, record.Session) &&
+		matchExact(f.Status, record.Status) &&
+		matchGlob(f.Server, record.Server) &&
+		matchGlob(f.Tool, record.Tool) &&
+		matchGlob(f.Prompt, record.Prompt) &&
+		matchGlob(f.Resource, record.Resource)
+}
+
+func matchExact(expected, value string) bool {
+	return expected == "" || strings.EqualFold(expected, value)
+}
+
+func matchGlob(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	if matched, err := filepath.Match(pattern, value); err == nil && matched {
+		return true
+	}
+	return strings.EqualFold(pattern, value)
+}
+
+// Read returns the records of the audit log, including its rotated backups,
+// that match the filter. Records are sorted from the oldest to the newest.
+func Read(path string, filter Filter) ([]Record, error) {
+	candidates, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	// Only numbered files are backups. Others, like locks or temporary files,
+	// are not part of the log.
+	var backups []string
+	for _, candidate := range candidates {
+		if _, ok := backupIndex(path, candidate); ok {
+			backups = append(backups, candidate)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		// Higher index means older.
+		indexI, _ := backupIndex(path, backups[i])
+		indexJ, _ := backupIndex(path, backups[j])
+		return indexI > indexJ
+	})
+
+	var records []Record
+	for _, file := range append(backups, path) {
+		fileRecords, err := readFile(file, filter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+
+	return records, nil
+}
+
+func backupIndex(path, backup string) (int, bool) {
+	suffix := strings.TrimPrefix(backup, path+".")
+	if suffix == "" || strings.Trim(suffix, "0123456789") != "" {
+		return 0, false
+	}
+	index, err := strconv.Atoi(suffix)
+	return index, err == nil
+}
+
+func readFile(path string, filter Filter) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("parsing audit log %s: %w", path, err)
+		}
+
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	logger, err := Open(path, 0, 0)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	require.NoError(t, logger.Log(Record{Timestamp: now, Operation: OperationToolCall, Server: "github", Tool: "create_issue", Status: StatusSuccess}))
+	require.NoError(t, logger.Log(Record{Timestamp: now.Add(time.Second), Operation: OperationPromptGet, Server: "github", Prompt: "summarize", Status: StatusFailure}))
+	require.NoError(t, logger.Close())
+
+	records, err := Read(path, Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "create_issue", records[0].Tool)
+	assert.Equal(t, "summarize", records[1].Prompt)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	logger, err := Open(path, 200, 2)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	start := time.Now().UTC()
+	for i := range 10 {
+		require.NoError(t, logger.Log(Record{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Operation: OperationToolCall,
+			Tool:      strings.Repeat("t", 10),
+			Status:    StatusSuccess,
+		}))
+	}
+
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	for _, file := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(200))
+	}
+
+	records, err := Read(path, Filter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	for i := 1; i < len(records); i++ {
+		assert.True(t, records[i-1].Timestamp.Before(records[i].Timestamp))
+	}
+	// The oldest records were dropped with the oldest backup.
+	assert.True(t, records[0].Timestamp.After(start))
+}
+
+func TestReadIgnoresOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	logger, err := Open(path, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, logger.Log(Record{Operation: OperationToolCall, Tool: "search", Status: StatusSuccess}))
+	require.NoError(t, logger.Close())
+
+	require.NoError(t, os.WriteFile(path+".lock", []byte("not json"), 0o600))
+	require.NoError(t, os.WriteFile(path+".tmp", []byte("not json"), 0o600))
+	require.NoError(t, os.WriteFile(path+".1.bak", []byte("not json"), 0o600))
+
+	records, err := Read(path, Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "search", records[0].Tool)
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	record := Record{
+		Timestamp: now,
+		Operation: OperationToolCall,
+		Client:    "claude-ai",
+		Server:    "github",
+		Tool:      "create_issue",
+		Status:    StatusError,
+	}
+
+	assert.True(t, Filter{}.Match(record))
+	assert.True(t, Filter{Server: "git*", Tool: "create_*"}.Match(record))
+	assert.True(t, Filter{Client: "Claude-AI", Status: StatusError}.Match(record))
+	assert.False(t, Filter{Status: StatusSuccess}.Match(record))
+	assert.False(t, Filter{Tool: "delete_*"}.Match(record))
+	assert.False(t, Filter{Since: now.Add(time.Minute)}.Match(record))
+	assert.False(t, Filter{Until: now.Add(-time.Minute)}.Match(record))
+}
+
+func TestDigestIsStable(t *testing.T) {
+	first := Digest(map[string]any{"a": 1, "b": "two"})
+	second := Digest(json.RawMessage(`{"b":"two","a":1}`))
+	third := Digest(map[string]any{"a": 1, "b": "three"})
+
+	assert.True(t, strings.HasPrefix(first, "sha256:"))
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, third)
+	assert.Empty(t, Digest(nil))
+}
+
+func TestRedact(t *testing.T) {
+	redacted := Redact(map[string]any{
+		"query":    "SELECT 1",
+		"apiToken": "abc",
+		"nested": map[string]any{
+			"password": "hunter2",
+			"name":     "bob",
+		},
+	})
+
+	assert.Equal(t, "SELECT 1", redacted["query"])
+	assert.Equal(t, "[REDACTED]", redacted["apiToken"])
+	assert.Equal(t, map[string]any{"password": "[REDACTED]", "name": "bob"}, redacted["nested"])
+}
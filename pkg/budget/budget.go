@@ -0,0 +1,224 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// StrategyDescriptions shortens tool descriptions to their first sentence.
+	StrategyDescriptions = "descriptions"
+	// StrategySchemaDescriptions drops the descriptions of optional arguments.
+	StrategySchemaDescriptions = "schema-descriptions"
+	// StrategyHide hides the least used tools.
+	StrategyHide = "hide"
+
+	// A rough but stable estimate, good enough to compare tools with one another.
+	bytesPerToken        = 4
+	maxDescriptionLength = 200
+)
+
+// ServerMetaKey is the key, in the _meta of a tool listed by the gateway, of
+// the server that provides the tool.
+const ServerMetaKey = "io.docker.mcp/server"
+
+var strategies = []string{StrategyDescriptions, StrategySchemaDescriptions, StrategyHide}
+
+// Tool is a tool competing for the budget.
+type Tool struct {
+	Server string
+	Tool   *mcp.Tool
+	// Calls is how many times the tool was called recently. Tools with fewer
+	// calls are hidden first.
+	Calls int
+}
+
+// Result lists the tools that fit in the budget, possibly trimmed, and those
+// that had to be hidden.
+type Result struct {
+	Tools  []Tool
+	Hidden []Tool
+	Before int
+	After  int
+}
+
+// Estimate returns the approximate number of tokens a tool uses in a
+// tools/list response: its name, description and schemas. Its _meta is not
+// meant for the model and isn't counted.
+func Estimate(tool *mcp.Tool) int {
+	withoutMeta := *tool
+	withoutMeta.Meta = nil
+
+	buf, err := json.Marshal(&withoutMeta)
+	if err != nil {
+		return 0
+	}
+	return (len(buf) + bytesPerToken - 1) / bytesPerToken
+}
+
+func Total(tools []Tool) int {
+	total := 0
+	for _, tool := range tools {
+		total += Estimate(tool.Tool)
+	}
+	return total
+}
+
+func ValidateStrategies(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(strategies, name) {
+			return fmt.Errorf("unknown budget strategy %q: must be one of %s", name, strings.Join(strategies, ", "))
+		}
+	}
+	return nil
+}
+
+// Enforce trims tools until they fit in the budget, using the enabled
+// strategies in a fixed order, from the least to the most disruptive. Each
+// strategy stops as soon as the tools fit. The tools given as input are never
+// modified.
+func Enforce(tools []Tool, budget int, enabled []string) Result {
+	kept := make([]Tool, len(tools))
+	copy(kept, tools)
+
+	result := Result{Before: Total(tools)}
+	total := result.Before
+
+	if total > budget && slices.Contains(enabled, StrategyDescriptions) {
+		total = trim(kept, total, budget, func(tool *mcp.Tool) *mcp.Tool {
+			short := shortenDescription(tool.Description)
+			if short == tool.Description {
+				return nil
+			}
+			trimmed := *tool
+			trimmed.Description = short
+			return &trimmed
+		})
+	}
+
+	if total > budget && slices.Contains(enabled, StrategySchemaDescriptions) {
+		total = trim(kept, total, budget, dropOptionalDescriptions)
+	}
+
+	if total > budget && slices.Contains(enabled, StrategyHide) {
+		order := make([]int, len(kept))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			a, b := kept[order[i]], kept[order[j]]
+			if a.Calls != b.Calls {
+				return a.Calls < b.Calls
+			}
+			return Estimate(a.Tool) > Estimate(b.Tool)
+		})
+
+		hidden := map[int]bool{}
+		for _, i := range order {
+			if total <= budget {
+				break
+			}
+			hidden[i] = true
+			total -= Estimate(kept[i].Tool)
+		}
+
+		var visible []Tool
+		for i, tool := range kept {
+			if hidden[i] {
+				result.Hidden = append(result.Hidden, tool)
+			} else {
+				visible = append(visible, tool)
+			}
+		}
+		kept = visible
+	}
+
+	result.Tools = kept
+	result.After = total
+	return result
+}
+
+// trim applies a transformation to the biggest tools first, until the total
+// fits in the budget. The transformation returns nil when it can't reduce a tool.
+func trim(tools []Tool, total, budget int, transform func(*mcp.Tool) *mcp.Tool) int {
+	order := make([]int, len(tools))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return Estimate(tools[order[i]].Tool) > Estimate(tools[order[j]].Tool)
+	})
+
+	for _, i := range order {
+		if total <= budget {
+			break
+		}
+
+		trimmed := transform(tools[i].Tool)
+		if trimmed == nil {
+			continue
+		}
+
+		total -= Estimate(tools[i].Tool) - Estimate(trimmed)
+		tools[i].Tool = trimmed
+	}
+
+	return total
+}
+
+// shortenDescription keeps the first paragraph, then the first sentence of a
+// description that's too long.
+func shortenDescription(description string) string {
+	short := strings.TrimSpace(description)
+	if len(short) <= maxDescriptionLength {
+		return short
+	}
+
+	if paragraph, _, found := strings.Cut(short, "\n\n"); found {
+		short = strings.TrimSpace(paragraph)
+	}
+	if len(short) > maxDescriptionLength {
+		if sentence, _, found := strings.Cut(short, ". "); found {
+			short = sentence + "."
+		}
+	}
+	if len(short) > maxDescriptionLength {
+		length := maxDescriptionLength
+		for length > 0 && !utf8.RuneStart(short[length]) {
+			length--
+		}
+		short = strings.TrimSpace(short[:length]) + "..."
+	}
+
+	return short
+}
+
+func dropOptionalDescriptions(tool *mcp.Tool) *mcp.Tool {
+	if tool.InputSchema == nil {
+		return nil
+	}
+
+	var optional []string
+	for name, property := range tool.InputSchema.Properties {
+		if property != nil && property.Description != "" && !slices.Contains(tool.InputSchema.Required, name) {
+			optional = append(optional, name)
+		}
+	}
+	if len(optional) == 0 {
+		return nil
+	}
+
+	trimmed := *tool
+	trimmed.InputSchema = tool.InputSchema.CloneSchemas()
+	for _, name := range optional {
+		trimmed.InputSchema.Properties[name].Description = ""
+	}
+
+	return &trimmed
+}
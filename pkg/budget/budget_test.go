@@ -0,0 +1,107 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tool(name, description string) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {Type: "string", Description: "What to search for"},
+				"limit": {Type: "integer", Description: strings.Repeat("The maximum number of results. ", 10)},
+			},
+			Required: []string{"query"},
+		},
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	small := Estimate(tool("search", "Search."))
+	big := Estimate(tool("search", strings.Repeat("Search the web. ", 100)))
+
+	assert.Positive(t, small)
+	assert.InDelta(t, 400, big-small, 5)
+}
+
+func TestEnforceWithinBudget(t *testing.T) {
+	tools := []Tool{{Server: "web", Tool: tool("search", "Search.")}}
+
+	result := Enforce(tools, 10000, strategies)
+
+	assert.Equal(t, tools, result.Tools)
+	assert.Empty(t, result.Hidden)
+	assert.Equal(t, result.Before, result.After)
+}
+
+func TestEnforceShortensDescriptions(t *testing.T) {
+	long := tool("search", "Search the web. "+strings.Repeat("It supports many operators. ", 50))
+	tools := []Tool{{Server: "web", Tool: long}}
+
+	result := Enforce(tools, Estimate(long)-100, []string{StrategyDescriptions})
+
+	require.Len(t, result.Tools, 1)
+	assert.Equal(t, "Search the web.", result.Tools[0].Tool.Description)
+	assert.Less(t, result.After, result.Before)
+	assert.Equal(t, result.After, Total(result.Tools))
+	// The input is left untouched.
+	assert.Contains(t, long.Description, "operators")
+}
+
+func TestEnforceDropsOptionalDescriptions(t *testing.T) {
+	original := tool("search", "Search.")
+	tools := []Tool{{Server: "web", Tool: original}}
+
+	result := Enforce(tools, Estimate(original)-10, []string{StrategyDescriptions, StrategySchemaDescriptions})
+
+	require.Len(t, result.Tools, 1)
+	schema := result.Tools[0].Tool.InputSchema
+	assert.Empty(t, schema.Properties["limit"].Description)
+	assert.Equal(t, "What to search for", schema.Properties["query"].Description)
+	assert.NotEmpty(t, original.InputSchema.Properties["limit"].Description)
+}
+
+func TestEnforceHidesLeastUsedTools(t *testing.T) {
+	tools := []Tool{
+		{Server: "web", Tool: tool("search", "Search."), Calls: 10},
+		{Server: "web", Tool: tool("fetch", "Fetch."), Calls: 0},
+		{Server: "git", Tool: tool("log", "Log."), Calls: 3},
+	}
+
+	result := Enforce(tools, Total(tools)-1, []string{StrategyHide})
+
+	require.Len(t, result.Hidden, 1)
+	assert.Equal(t, "fetch", result.Hidden[0].Tool.Name)
+	require.Len(t, result.Tools, 2)
+	assert.Equal(t, "search", result.Tools[0].Tool.Name)
+	assert.Equal(t, "log", result.Tools[1].Tool.Name)
+}
+
+func TestEnforceWithoutStrategies(t *testing.T) {
+	tools := []Tool{{Server: "web", Tool: tool("search", "Search.")}}
+
+	result := Enforce(tools, 1, nil)
+
+	assert.Len(t, result.Tools, 1)
+	assert.Greater(t, result.After, 1)
+}
+
+func TestShortenDescription(t *testing.T) {
+	assert.Equal(t, "Short.", shortenDescription("  Short.  "))
+	assert.Equal(t, "First paragraph.", shortenDescription("First paragraph.\n\n"+strings.Repeat("More. ", 50)))
+	assert.Equal(t, strings.Repeat("é", 100)+"...", shortenDescription(strings.Repeat("é", 150)))
+}
+
+func TestValidateStrategies(t *testing.T) {
+	require.NoError(t, ValidateStrategies([]string{StrategyDescriptions, StrategyHide}))
+	require.ErrorContains(t, ValidateStrategies([]string{"compress"}), `unknown budget strategy "compress"`)
+}
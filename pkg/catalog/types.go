@@ -1,5 +1,12 @@
 package catalog
 
+import (
+	"encoding/json"
+	"maps"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
 type Catalog struct {
 	Servers map[string]Server
 }
@@ -13,23 +20,60 @@ type topLevel struct {
 // MCP Servers
 
 type Server struct {
-	Name           string   `yaml:"name,omitempty" json:"name,omitempty"`
-	Type           string   `yaml:"type" json:"type"`
-	Image          string   `yaml:"image" json:"image"`
-	Description    string   `yaml:"description,omitempty" json:"description,omitempty"`
-	LongLived      bool     `yaml:"longLived,omitempty" json:"longLived,omitempty"`
-	Remote         Remote   `yaml:"remote,omitempty" json:"remote,omitempty"`
-	SSEEndpoint    string   `yaml:"sseEndpoint,omitempty" json:"sseEndpoint,omitempty"` // Deprecated: Use Remote instead
-	OAuth          *OAuth   `yaml:"oauth,omitempty" json:"oauth,omitempty"`
-	Secrets        []Secret `yaml:"secrets,omitempty" json:"secrets,omitempty"`
-	Env            []Env    `yaml:"env,omitempty" json:"env,omitempty"`
-	Command        []string `yaml:"command,omitempty" json:"command,omitempty"`
-	Volumes        []string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
-	User           string   `yaml:"user,omitempty" json:"user,omitempty"`
-	DisableNetwork bool     `yaml:"disableNetwork,omitempty" json:"disableNetwork,omitempty"`
-	AllowHosts     []string `yaml:"allowHosts,omitempty" json:"allowHosts,omitempty"`
-	Tools          []Tool   `yaml:"tools,omitempty" json:"tools,omitempty"`
-	Config         []any    `yaml:"config,omitempty" json:"config,omitempty"`
+	Name           string    `yaml:"name,omitempty" json:"name,omitempty"`
+	Type           string    `yaml:"type" json:"type"`
+	Image          string    `yaml:"image" json:"image"`
+	Package        *Package  `yaml:"package,omitempty" json:"package,omitempty"`
+	Description    string    `yaml:"description,omitempty" json:"description,omitempty"`
+	LongLived      bool      `yaml:"longLived,omitempty" json:"longLived,omitempty"`
+	Remote         Remote    `yaml:"remote,omitempty" json:"remote,omitempty"`
+	SSEEndpoint    string    `yaml:"sseEndpoint,omitempty" json:"sseEndpoint,omitempty"` // Deprecated: Use Remote instead
+	OAuth          *OAuth    `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+	Secrets        []Secret  `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	Env            []Env     `yaml:"env,omitempty" json:"env,omitempty"`
+	Command        []string  `yaml:"command,omitempty" json:"command,omitempty"`
+	Volumes        []string  `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	User           string    `yaml:"user,omitempty" json:"user,omitempty"`
+	DisableNetwork bool      `yaml:"disableNetwork,omitempty" json:"disableNetwork,omitempty"`
+	AllowHosts     []string  `yaml:"allowHosts,omitempty" json:"allowHosts,omitempty"`
+	Workspace      bool      `yaml:"workspace,omitempty" json:"workspace,omitempty"` // Mount the roots of the client under /workspace
+	Security       Security  `yaml:"security,omitempty" json:"security,omitempty"`
+	Resources      Resources `yaml:"resources,omitempty" json:"resources,omitempty"`
+	Tools          []Tool    `yaml:"tools,omitempty" json:"tools,omitempty"`
+	Config         []any     `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// Resources are the limits of a server's container. They can be overridden in
+// the server's config, under a resources key.
+type Resources struct {
+	CPUs    float64  `yaml:"cpus,omitempty" json:"cpus,omitempty"`
+	Memory  string   `yaml:"memory,omitempty" json:"memory,omitempty"`
+	Pids    int64    `yaml:"pids,omitempty" json:"pids,omitempty"`
+	Ulimits []string `yaml:"ulimits,omitempty" json:"ulimits,omitempty"` // eg. nofile=1024:2048
+	Runtime string   `yaml:"runtime,omitempty" json:"runtime,omitempty"`
+}
+
+// Package is a server that's published as an npm or PyPI package, or as a
+// binary, instead of an image. Its command holds the arguments passed to the
+// package.
+type Package struct {
+	Runner  string `yaml:"runner" json:"runner"`                       // npx, uvx or binary
+	Name    string `yaml:"name" json:"name"`                           // Name of the package, or absolute path of the binary
+	Version string `yaml:"version,omitempty" json:"version,omitempty"` // Defaults to the latest version
+	// RuntimeArgs are passed to the runner, before the name of the package.
+	RuntimeArgs []string `yaml:"runtimeArgs,omitempty" json:"runtimeArgs,omitempty"`
+}
+
+// Security lets a server opt out of parts of the hardened profile its
+// container runs with. Running as a non-root user is opt-in instead, since
+// many images need to write to paths only root can write to.
+type Security struct {
+	WritableRootFS   bool     `yaml:"writableRootfs,omitempty" json:"writableRootfs,omitempty"`
+	KeepCapabilities bool     `yaml:"keepCapabilities,omitempty" json:"keepCapabilities,omitempty"`
+	CapAdd           []string `yaml:"capAdd,omitempty" json:"capAdd,omitempty"`
+	NoPidsLimit      bool     `yaml:"noPidsLimit,omitempty" json:"noPidsLimit,omitempty"`
+	RunAsNonRoot     bool     `yaml:"runAsNonRoot,omitempty" json:"runAsNonRoot,omitempty"`
+	DefaultSeccomp   bool     `yaml:"defaultSeccomp,omitempty" json:"defaultSeccomp,omitempty"`
 }
 
 type Secret struct {
@@ -59,9 +103,8 @@ type OAuthProvider struct {
 
 // POCI tools
 
-type Items struct {
-	Type string `yaml:"type" json:"type"`
-}
+// Items is the schema of the items of an array parameter.
+type Items = Property
 
 type Run struct {
 	Command []string          `yaml:"command,omitempty" json:"command,omitempty"`
@@ -94,39 +137,132 @@ type Parameters struct {
 
 type Properties map[string]Property
 
+// Property is a subset of JSON Schema, enough to describe the parameters of
+// a tool. Objects can be nested.
 type Property struct {
-	Type        string `yaml:"type" json:"type"`
-	Description string `yaml:"description" json:"description"`
-	Items       *Items `yaml:"items,omitempty" json:"items,omitempty"`
+	Type        string     `yaml:"type" json:"type"`
+	Description string     `yaml:"description" json:"description"`
+	Items       *Items     `yaml:"items,omitempty" json:"items,omitempty"`
+	Enum        []any      `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Default     any        `yaml:"default,omitempty" json:"default,omitempty"`
+	Format      string     `yaml:"format,omitempty" json:"format,omitempty"`
+	Properties  Properties `yaml:"properties,omitempty" json:"properties,omitempty"`
+	Required    []string   `yaml:"required,omitempty" json:"required,omitempty"`
 }
 
 type Container struct {
-	Image   string   `yaml:"image" json:"image"`
-	Command []string `yaml:"command" json:"command"`
-	Volumes []string `yaml:"volumes" json:"volumes"`
-	User    string   `yaml:"user,omitempty" json:"user,omitempty"`
+	Image          string    `yaml:"image" json:"image"`
+	Command        []string  `yaml:"command" json:"command"`
+	Volumes        []string  `yaml:"volumes" json:"volumes"`
+	User           string    `yaml:"user,omitempty" json:"user,omitempty"`
+	Env            []Env     `yaml:"env,omitempty" json:"env,omitempty"`
+	Secrets        []Secret  `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	WorkingDir     string    `yaml:"workingDir,omitempty" json:"workingDir,omitempty"`
+	Stdin          string    `yaml:"stdin,omitempty" json:"stdin,omitempty"`     // Written to the container's stdin, eg. '{{content}}'
+	Timeout        string    `yaml:"timeout,omitempty" json:"timeout,omitempty"` // Go duration, eg. 30s
+	DisableNetwork bool      `yaml:"disableNetwork,omitempty" json:"disableNetwork,omitempty"`
+	AllowHosts     []string  `yaml:"allowHosts,omitempty" json:"allowHosts,omitempty"`
+	Resources      Resources `yaml:"resources,omitempty" json:"resources,omitempty"`
 }
 
 func (p *Properties) ToMap() map[string]any {
 	m := map[string]any{}
 
 	for k, v := range *p {
-		propMap := map[string]any{
-			"type":        v.Type,
-			"description": v.Description,
-		}
+		m[k] = v.toMap()
+	}
 
-		// Include items property for arrays
-		if v.Type == "array" && v.Items != nil {
-			propMap["items"] = map[string]any{
-				"type": v.Items.Type,
-			}
+	return m
+}
+
+func (p *Property) toMap() map[string]any {
+	propMap := map[string]any{}
+	if p.Type != "" {
+		propMap["type"] = p.Type
+	}
+	if p.Description != "" {
+		propMap["description"] = p.Description
+	}
+
+	// Include items property for arrays
+	if p.Type == "array" && p.Items != nil {
+		propMap["items"] = p.Items.toMap()
+	}
+	if len(p.Enum) > 0 {
+		propMap["enum"] = p.Enum
+	}
+	if p.Default != nil {
+		propMap["default"] = p.Default
+	}
+	if p.Format != "" {
+		propMap["format"] = p.Format
+	}
+	if len(p.Properties) > 0 {
+		propMap["properties"] = p.Properties.ToMap()
+	}
+	if len(p.Required) > 0 {
+		propMap["required"] = p.Required
+	}
+
+	return propMap
+}
+
+// Schema converts the parameters of a tool to the JSON Schema of its input.
+func (p *Parameters) Schema() (*jsonschema.Schema, error) {
+	schemaType := p.Type
+	if schemaType == "" {
+		schemaType = "object"
+	}
+
+	schemaMap := map[string]any{
+		"type": schemaType,
+	}
+	if len(p.Properties) > 0 {
+		schemaMap["properties"] = p.Properties.ToMap()
+	}
+	if len(p.Required) > 0 {
+		schemaMap["required"] = p.Required
+	}
+
+	buf, err := json.Marshal(schemaMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(buf, &schema); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+// WithDefaults returns a copy of the arguments of a tool, where the missing
+// parameters get their default value, if they have one. Nested objects get
+// their defaults too.
+func (p *Parameters) WithDefaults(arguments map[string]any) map[string]any {
+	return p.Properties.withDefaults(arguments)
+}
+
+func (p Properties) withDefaults(arguments map[string]any) map[string]any {
+	withDefaults := maps.Clone(arguments)
+	if withDefaults == nil {
+		withDefaults = map[string]any{}
+	}
+
+	for name, property := range p {
+		value, found := withDefaults[name]
+		if !found && property.Default != nil {
+			withDefaults[name] = property.Default
+			continue
 		}
 
-		m[k] = propMap
+		if object, isObject := value.(map[string]any); isObject && len(property.Properties) > 0 {
+			withDefaults[name] = property.Properties.withDefaults(object)
+		}
 	}
 
-	return m
+	return withDefaults
 }
 
 // Config
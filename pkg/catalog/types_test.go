@@ -0,0 +1,84 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParametersSchema(t *testing.T) {
+	var parameters Parameters
+	require.NoError(t, yaml.Unmarshal([]byte(`
+properties:
+  format:
+    type: string
+    enum: [json, yaml]
+    default: json
+  since:
+    type: string
+    format: date-time
+  tags:
+    type: array
+    items:
+      type: string
+  options:
+    type: object
+    properties:
+      depth:
+        type: integer
+    required: [depth]
+required: [format]
+`), &parameters))
+
+	schema, err := parameters.Schema()
+	require.NoError(t, err)
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"format"}, schema.Required)
+	assert.Equal(t, []any{"json", "yaml"}, schema.Properties["format"].Enum)
+	assert.JSONEq(t, `"json"`, string(schema.Properties["format"].Default))
+	assert.Equal(t, "date-time", schema.Properties["since"].Format)
+	assert.Equal(t, "string", schema.Properties["tags"].Items.Type)
+	assert.Equal(t, "integer", schema.Properties["options"].Properties["depth"].Type)
+	assert.Equal(t, []string{"depth"}, schema.Properties["options"].Required)
+}
+
+func TestParametersWithDefaults(t *testing.T) {
+	var parameters Parameters
+	require.NoError(t, yaml.Unmarshal([]byte(`
+properties:
+  format:
+    type: string
+    default: json
+  limit:
+    type: integer
+    default: 10
+  query:
+    type: string
+  options:
+    type: object
+    properties:
+      depth:
+        type: integer
+        default: 2
+`), &parameters))
+
+	arguments := map[string]any{"limit": 5, "options": map[string]any{}}
+	withDefaults := parameters.WithDefaults(arguments)
+
+	assert.Equal(t, map[string]any{"format": "json", "limit": 5, "options": map[string]any{"depth": 2}}, withDefaults)
+	assert.Equal(t, map[string]any{"limit": 5, "options": map[string]any{}}, arguments)
+	assert.Equal(t, map[string]any{"format": "json", "limit": 10}, parameters.WithDefaults(nil))
+}
+
+func TestParametersSchemaEmpty(t *testing.T) {
+	var parameters Parameters
+
+	schema, err := parameters.Schema()
+	require.NoError(t, err)
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Empty(t, schema.Properties)
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+// FilterConfig lists, server by server, the prompts or the resources that are
+// enabled, the same way tools.yaml lists tools. Servers that are not listed
+// expose everything.
+type FilterConfig struct {
+	Servers map[string][]string `yaml:",inline"`
+}
+
+func ParseFilterConfig(filterYaml []byte) (FilterConfig, error) {
+	var filterConfig FilterConfig
+	if err := yaml.Unmarshal(filterYaml, &filterConfig); err != nil {
+		return FilterConfig{}, err
+	}
+
+	if filterConfig.Servers == nil {
+		filterConfig.Servers = make(map[string][]string)
+	}
+
+	return filterConfig, nil
+}
+
+// IsEnabled tells whether a prompt or a resource is enabled. A resource can be
+// identified by several names, eg. its URI and its name.
+func (c FilterConfig) IsEnabled(serverName string, names ...string) bool {
+	enabled, exists := c.Servers[serverName]
+	if !exists {
+		return true
+	}
+
+	for _, name := range names {
+		if slices.Contains(enabled, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Enable adds names to the list of a server. Servers that are not listed
+// already expose everything.
+func (c FilterConfig) Enable(serverName string, names []string) {
+	enabled, exists := c.Servers[serverName]
+	if !exists {
+		return
+	}
+
+	for _, name := range names {
+		if !slices.Contains(enabled, name) {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	c.Servers[serverName] = enabled
+}
+
+// Disable removes names from the list of a server. all returns every prompt
+// or resource the server exposes, each one identified by one or several
+// names, the first of which goes in the list. It's only called when the
+// server is not listed yet, or when a name is not found in the list.
+func (c FilterConfig) Disable(serverName string, names []string, all func() ([][]string, error)) error {
+	enabled, exists := c.Servers[serverName]
+	if exists && !slices.ContainsFunc(names, func(name string) bool { return !slices.Contains(enabled, name) }) {
+		c.Servers[serverName] = slices.DeleteFunc(enabled, func(name string) bool { return slices.Contains(names, name) })
+		return nil
+	}
+
+	items, err := all()
+	if err != nil {
+		return err
+	}
+
+	disabled := func(item []string) bool {
+		return slices.ContainsFunc(item, func(name string) bool { return slices.Contains(names, name) })
+	}
+
+	updated := []string{}
+	if exists {
+		for _, name := range enabled {
+			if slices.Contains(names, name) || slices.ContainsFunc(items, func(item []string) bool { return slices.Contains(item, name) && disabled(item) }) {
+				continue
+			}
+			updated = append(updated, name)
+		}
+	} else {
+		for _, item := range items {
+			if len(item) > 0 && !disabled(item) {
+				updated = append(updated, item[0])
+			}
+		}
+	}
+	sort.Strings(updated)
+	c.Servers[serverName] = updated
+
+	return nil
+}
+
+// EnableFiltered enables prompts or resources of a server, in the prompts or
+// resources file.
+func EnableFiltered(ctx context.Context, docker docker.Client, name string, serverName string, names []string) error {
+	return UpdateFilterConfig(ctx, docker, name, serverName, func(filterConfig FilterConfig) error {
+		filterConfig.Enable(serverName, names)
+		return nil
+	})
+}
+
+// DisableFiltered disables prompts or resources of a server, in the prompts or
+// resources file. See FilterConfig.Disable for all.
+func DisableFiltered(ctx context.Context, docker docker.Client, name string, serverName string, names []string, all func() ([][]string, error)) error {
+	return UpdateFilterConfig(ctx, docker, name, serverName, func(filterConfig FilterConfig) error {
+		return filterConfig.Disable(serverName, names, all)
+	})
+}
+
+// UpdateFilterConfig reads a prompts or resources file, lets update change it
+// and writes it back. The server must be enabled, so that a typo in its name
+// doesn't go unnoticed.
+func UpdateFilterConfig(ctx context.Context, docker docker.Client, name string, serverName string, update func(FilterConfig) error) error {
+	kind := strings.TrimSuffix(name, ".yaml")
+	if serverName == "" {
+		return errors.New("a server name is required")
+	}
+
+	registryYAML, err := ReadRegistry(ctx, docker)
+	if err != nil {
+		return fmt.Errorf("reading registry: %w", err)
+	}
+	registry, err := ParseRegistryConfig(registryYAML)
+	if err != nil {
+		return fmt.Errorf("parsing registry: %w", err)
+	}
+	if _, enabled := registry.Servers[serverName]; !enabled {
+		return fmt.Errorf("server %s is not enabled", serverName)
+	}
+
+	filterYAML, err := ReadConfigFile(ctx, docker, name)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", kind, err)
+	}
+
+	filterConfig, err := ParseFilterConfig(filterYAML)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", kind, err)
+	}
+
+	if err := update(filterConfig); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(filterConfig.Servers); err != nil {
+		return fmt.Errorf("encoding %s: %w", kind, err)
+	}
+
+	if err := writeConfigFile(name, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing %s: %w", kind, err)
+	}
+
+	return nil
+}
@@ -15,6 +15,14 @@ func ReadTools(ctx context.Context, docker docker.Client) ([]byte, error) {
 	return ReadConfigFile(ctx, docker, "tools.yaml")
 }
 
+func ReadPrompts(ctx context.Context, docker docker.Client) ([]byte, error) {
+	return ReadConfigFile(ctx, docker, "prompts.yaml")
+}
+
+func ReadResources(ctx context.Context, docker docker.Client) ([]byte, error) {
+	return ReadConfigFile(ctx, docker, "resources.yaml")
+}
+
 func ReadConfig(ctx context.Context, docker docker.Client) ([]byte, error) {
 	return ReadConfigFile(ctx, docker, "config.yaml")
 }
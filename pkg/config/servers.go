@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const serversInfoFilename = "servers.json"
+
+// ServerInfo is what the gateway learnt about an MCP server when it last connected to it.
+type ServerInfo struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	Name            string `json:"name,omitempty"`
+	Version         string `json:"version,omitempty"`
+	// Since is when the gateway first saw the server with this version and protocol version.
+	Since time.Time `json:"since"`
+}
+
+var serversInfoLock sync.Mutex
+
+func ReadServersInfo() (map[string]ServerInfo, error) {
+	path, err := FilePath(serversInfoFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := readFileOrEmpty(path)
+	if err != nil {
+		return nil, err
+	}
+
+	serversInfo := map[string]ServerInfo{}
+	if len(buf) == 0 {
+		return serversInfo, nil
+	}
+	if err := json.Unmarshal(buf, &serversInfo); err != nil {
+		return nil, err
+	}
+
+	return serversInfo, nil
+}
+
+// WriteServerInfo saves what was learnt about a server. The file is only
+// rewritten when something changed, since the gateway connects to servers all
+// the time and several gateways can share the same file. It's replaced
+// atomically, so that other gateways never read a partial file.
+func WriteServerInfo(serverName string, info ServerInfo) error {
+	serversInfoLock.Lock()
+	defer serversInfoLock.Unlock()
+
+	serversInfo, err := ReadServersInfo()
+	if err != nil {
+		return err
+	}
+	if previous, found := serversInfo[serverName]; found && previous.SameAs(info) {
+		return nil
+	}
+	serversInfo[serverName] = info
+
+	buf, err := json.MarshalIndent(serversInfo, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := FilePath(serversInfoFilename)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), serversInfoFilename+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// SameAs tells whether two infos describe the same server, whenever they were recorded.
+func (i ServerInfo) SameAs(other ServerInfo) bool {
+	return i.ProtocolVersion == other.ProtocolVersion && i.Name == other.Name && i.Version == other.Version
+}
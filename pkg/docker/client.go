@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
@@ -21,6 +22,10 @@ type Client interface {
 	ContainerExists(ctx context.Context, container string) (bool, container.InspectResponse, error)
 	RemoveContainer(ctx context.Context, containerID string, force bool) error
 	StartContainer(ctx context.Context, containerID string, containerConfig container.Config, hostConfig container.HostConfig, networkingConfig network.NetworkingConfig) error
+	CreateContainer(ctx context.Context, name string, containerConfig container.Config, hostConfig container.HostConfig, networkingConfig network.NetworkingConfig) (string, error)
+	AttachContainer(ctx context.Context, containerID string) (types.HijackedResponse, error)
+	StartCreatedContainer(ctx context.Context, containerID string) error
+	WaitContainer(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
 	StopContainer(ctx context.Context, containerID string, timeout int) error
 	FindContainerByLabel(ctx context.Context, label string) (string, error)
 	FindAllContainersByLabel(ctx context.Context, label string) ([]string, error)
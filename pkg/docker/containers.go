@@ -7,6 +7,7 @@ import (
 	"io"
 
 	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
@@ -40,6 +41,38 @@ func (c *dockerClient) StartContainer(ctx context.Context, containerID string, c
 	return nil
 }
 
+func (c *dockerClient) CreateContainer(ctx context.Context, name string, containerConfig container.Config, hostConfig container.HostConfig, networkingConfig network.NetworkingConfig) (string, error) {
+	resp, err := c.apiClient().ContainerCreate(ctx, &containerConfig, &hostConfig, &networkingConfig, nil, name)
+	if err != nil {
+		return "", fmt.Errorf("creating container: %w", err)
+	}
+
+	return resp.ID, nil
+}
+
+// AttachContainer attaches to the stdin, stdout and stderr of a container. Unless
+// the container has a TTY, stdout and stderr are multiplexed.
+func (c *dockerClient) AttachContainer(ctx context.Context, containerID string) (types.HijackedResponse, error) {
+	return c.apiClient().ContainerAttach(ctx, containerID, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+}
+
+func (c *dockerClient) StartCreatedContainer(ctx context.Context, containerID string) error {
+	if err := c.apiClient().ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("starting container: %w", err)
+	}
+
+	return nil
+}
+
+func (c *dockerClient) WaitContainer(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	return c.apiClient().ContainerWait(ctx, containerID, condition)
+}
+
 func (c *dockerClient) StopContainer(ctx context.Context, containerID string, timeout int) error {
 	return c.apiClient().ContainerStop(ctx, containerID, container.StopOptions{
 		Timeout: &timeout,
@@ -22,8 +22,12 @@ func (c *dockerClient) RemoveNetwork(ctx context.Context, name string) error {
 	return c.apiClient().NetworkRemove(ctx, name)
 }
 
+// ConnectNetwork connects a container to a network, with hostname as an
+// alias unless it's empty.
 func (c *dockerClient) ConnectNetwork(ctx context.Context, networkName, container, hostname string) error {
-	return c.apiClient().NetworkConnect(ctx, networkName, container, &network.EndpointSettings{
-		Aliases: []string{hostname},
-	})
+	var settings network.EndpointSettings
+	if hostname != "" {
+		settings.Aliases = []string{hostname}
+	}
+	return c.apiClient().NetworkConnect(ctx, networkName, container, &settings)
 }
@@ -0,0 +1,267 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// How long a container has to exit once its stdin is closed, before it's removed.
+const stopTimeout = 5 * time.Second
+
+// ContainerSpec is everything needed to create a container, the equivalent of
+// the flags of `docker run`.
+type ContainerSpec struct {
+	Name             string
+	Config           container.Config
+	HostConfig       container.HostConfig
+	NetworkingConfig network.NetworkingConfig
+}
+
+// ExitError is returned when a container exits with a non-zero code.
+type ExitError struct {
+	Code int64
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("container exited with code %d", e.Code)
+}
+
+// AttachedContainer is a running container whose stdin and stdout are
+// available as a stream. Its stderr is copied to the writer given when it was
+// started.
+type AttachedContainer struct {
+	ID string
+
+	client   Client
+	hijacked types.HijackedResponse
+	stdout   *io.PipeReader
+	// Closed once nothing is written to stdout and stderr anymore.
+	copied chan struct{}
+
+	exited   chan struct{}
+	exitCode int64
+	exitErr  error
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// createContainer creates a container connected to all the networks of its
+// spec. Daemons older than API 1.44 reject more than one network at creation,
+// so the container is created on its network mode and connected to the other
+// networks before it starts.
+func createContainer(ctx context.Context, client Client, spec ContainerSpec) (string, error) {
+	networkingConfig, others := splitNetworks(spec)
+
+	id, err := client.CreateContainer(ctx, spec.Name, spec.Config, spec.HostConfig, networkingConfig)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range others {
+		if err := client.ConnectNetwork(ctx, name, id, ""); err != nil {
+			_ = client.RemoveContainer(context.WithoutCancel(ctx), id, true)
+			return "", fmt.Errorf("connecting to network %s: %w", name, err)
+		}
+	}
+
+	return id, nil
+}
+
+// splitNetworks separates the network a container is created on, its network
+// mode, from the other networks it's connected to.
+func splitNetworks(spec ContainerSpec) (network.NetworkingConfig, []string) {
+	endpoints := spec.NetworkingConfig.EndpointsConfig
+	if len(endpoints) <= 1 {
+		return spec.NetworkingConfig, nil
+	}
+
+	names := slices.Sorted(maps.Keys(endpoints))
+	first := string(spec.HostConfig.NetworkMode)
+	if _, found := endpoints[first]; !found {
+		first = names[0]
+	}
+
+	networkingConfig := network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{first: endpoints[first]},
+	}
+	return networkingConfig, slices.DeleteFunc(names, func(name string) bool { return name == first })
+}
+
+// StartAttached creates a container, attaches to its streams and starts it. The
+// container should be created with OpenStdin and StdinOnce, so that closing its
+// stdin tells it to stop.
+func StartAttached(ctx context.Context, client Client, spec ContainerSpec, stderr io.Writer) (*AttachedContainer, error) {
+	spec.Config.AttachStdin = true
+	spec.Config.AttachStdout = true
+	spec.Config.AttachStderr = true
+	spec.Config.OpenStdin = true
+	spec.Config.StdinOnce = true
+
+	id, err := createContainer(ctx, client, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	hijacked, err := client.AttachContainer(ctx, id)
+	if err != nil {
+		_ = client.RemoveContainer(context.WithoutCancel(ctx), id, true)
+		return nil, fmt.Errorf("attaching to container: %w", err)
+	}
+
+	// Wait for the exit before starting the container, so that an auto-removed
+	// container that exits quickly isn't missed.
+	waitCtx, cancelWait := context.WithCancel(context.WithoutCancel(ctx))
+	waitC, errC := client.WaitContainer(waitCtx, id, container.WaitConditionNextExit)
+
+	if err := client.StartCreatedContainer(ctx, id); err != nil {
+		cancelWait()
+		hijacked.Close()
+		_ = client.RemoveContainer(context.WithoutCancel(ctx), id, true)
+		return nil, err
+	}
+
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	c := &AttachedContainer{
+		ID:       id,
+		client:   client,
+		hijacked: hijacked,
+		stdout:   stdoutReader,
+		copied:   make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	go func() {
+		defer close(c.copied)
+
+		_, err := stdcopy.StdCopy(stdoutWriter, stderr, hijacked.Reader)
+		_ = stdoutWriter.CloseWithError(err)
+	}()
+	go func() {
+		defer cancelWait()
+		defer close(c.exited)
+
+		select {
+		case resp := <-waitC:
+			c.exitCode = resp.StatusCode
+			if resp.Error != nil {
+				c.exitErr = errors.New(resp.Error.Message)
+			}
+		case err := <-errC:
+			c.exitErr = err
+		}
+	}()
+
+	return c, nil
+}
+
+// Read reads from the stdout of the container.
+func (c *AttachedContainer) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+// Write writes to the stdin of the container.
+func (c *AttachedContainer) Write(p []byte) (int, error) {
+	return c.hijacked.Conn.Write(p)
+}
+
+// CloseStdin closes the stdin of the container. Most MCP servers and tools stop
+// when their input is closed.
+func (c *AttachedContainer) CloseStdin() error {
+	return c.hijacked.CloseWrite()
+}
+
+// Wait waits for the container to exit and returns an *ExitError if its exit
+// code isn't zero.
+func (c *AttachedContainer) Wait(ctx context.Context) error {
+	select {
+	case <-c.exited:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if c.exitErr != nil {
+		return c.exitErr
+	}
+	if c.exitCode != 0 {
+		return &ExitError{Code: c.exitCode}
+	}
+	return nil
+}
+
+// Close closes the stdin of the container and gives it a few seconds to exit,
+// before forcefully removing it. Once it returns, nothing is written to the
+// stderr writer anymore.
+func (c *AttachedContainer) Close() error {
+	c.closeOnce.Do(func() {
+		_ = c.CloseStdin()
+
+		select {
+		case <-c.exited:
+		case <-time.After(stopTimeout):
+			if err := c.client.RemoveContainer(context.Background(), c.ID, true); err != nil && !cerrdefs.IsNotFound(err) {
+				c.closeErr = fmt.Errorf("removing container: %w", err)
+			}
+		}
+
+		_ = c.stdout.Close()
+		c.hijacked.Close()
+		<-c.copied
+	})
+
+	return c.closeErr
+}
+
+// RunContainer runs a container to completion, with the given stdin, and
+// copies its stdout and stderr. The container is removed if the context is
+// cancelled before it exits. Nothing is written to stdout and stderr once it
+// returns.
+func RunContainer(ctx context.Context, client Client, spec ContainerSpec, stdin io.Reader, stdout, stderr io.Writer) error {
+	c, err := StartAttached(ctx, client, spec, stderr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	go func() {
+		if stdin != nil {
+			_, _ = io.Copy(c, stdin)
+		}
+		_ = c.CloseStdin()
+	}()
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(stdout, c)
+		copied <- err
+	}()
+
+	select {
+	case err := <-copied:
+		if err != nil {
+			return fmt.Errorf("reading container output: %w", err)
+		}
+	case <-ctx.Done():
+		_ = client.RemoveContainer(context.WithoutCancel(ctx), c.ID, true)
+		_ = c.Close()
+		<-copied
+		return ctx.Err()
+	}
+
+	return c.Wait(ctx)
+}
@@ -0,0 +1,214 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunContainer(t *testing.T) {
+	fake := newFakeContainer(t, func(stdin io.Reader, stdout, stderr io.Writer) int64 {
+		in, _ := io.ReadAll(stdin)
+		_, _ = io.WriteString(stdout, strings.ToUpper(string(in)))
+		_, _ = io.WriteString(stderr, "some logs")
+		return 0
+	})
+
+	var stdout, stderr bytes.Buffer
+	err := RunContainer(t.Context(), fake, ContainerSpec{Config: container.Config{Image: "alpine"}}, strings.NewReader("hello"), &stdout, &stderr)
+	require.NoError(t, err)
+
+	assert.Equal(t, "HELLO", stdout.String())
+	assert.Equal(t, "some logs", stderr.String())
+	assert.Equal(t, "alpine", fake.config.Image)
+	assert.True(t, fake.config.OpenStdin)
+	assert.True(t, fake.config.StdinOnce)
+}
+
+func TestRunContainerNetworks(t *testing.T) {
+	fake := newFakeContainer(t, func(io.Reader, io.Writer, io.Writer) int64 { return 0 })
+
+	spec := ContainerSpec{
+		HostConfig: container.HostConfig{NetworkMode: "gateway"},
+		NetworkingConfig: network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{
+			"gateway":  {},
+			"proxy":    {},
+			"internal": {},
+		}},
+	}
+	err := RunContainer(t.Context(), fake, spec, nil, io.Discard, nil)
+	require.NoError(t, err)
+
+	// Only one network at creation, for daemons older than API 1.44.
+	assert.Equal(t, map[string]*network.EndpointSettings{"gateway": {}}, fake.networks.EndpointsConfig)
+	assert.Equal(t, []string{"internal", "proxy"}, fake.connected)
+}
+
+func TestRunContainerExitCode(t *testing.T) {
+	fake := newFakeContainer(t, func(_ io.Reader, stdout, _ io.Writer) int64 {
+		_, _ = io.WriteString(stdout, "failed")
+		return 3
+	})
+
+	var stdout bytes.Buffer
+	err := RunContainer(t.Context(), fake, ContainerSpec{}, nil, &stdout, nil)
+
+	var exitErr *ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, int64(3), exitErr.Code)
+	assert.Equal(t, "failed", stdout.String())
+}
+
+func TestRunContainerStartError(t *testing.T) {
+	fake := newFakeContainer(t, nil)
+	fake.startErr = errors.New("no such image")
+
+	err := RunContainer(t.Context(), fake, ContainerSpec{}, nil, io.Discard, nil)
+	require.ErrorContains(t, err, "no such image")
+	assert.True(t, fake.removed)
+}
+
+func TestRunContainerCancelled(t *testing.T) {
+	fake := newFakeContainer(t, func(_ io.Reader, stdout, stderr io.Writer) int64 {
+		for {
+			if _, err := io.WriteString(stdout, "out"); err != nil {
+				return 137
+			}
+			if _, err := io.WriteString(stderr, "err"); err != nil {
+				return 137
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	stdout := &lockedBuffer{onWrite: cancel}
+	stderr := &lockedBuffer{}
+	err := RunContainer(ctx, fake, ContainerSpec{}, nil, stdout, stderr)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.True(t, fake.removed)
+
+	// Nothing is written once RunContainer returned.
+	stdoutLen, stderrLen := stdout.Len(), stderr.Len()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stdoutLen, stdout.Len())
+	assert.Equal(t, stderrLen, stderr.Len())
+}
+
+type lockedBuffer struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	onWrite func()
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.onWrite != nil {
+		b.onWrite()
+	}
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
+
+// fakeContainer simulates a single container, whose process is a Go function,
+// attached to through a TCP connection like the Docker Engine API does.
+type fakeContainer struct {
+	Client
+	t        *testing.T
+	process  func(stdin io.Reader, stdout, stderr io.Writer) int64
+	config    container.Config
+	networks  network.NetworkingConfig
+	connected []string
+	startErr  error
+	removed  bool
+
+	server net.Conn
+	exit   chan container.WaitResponse
+}
+
+func newFakeContainer(t *testing.T, process func(stdin io.Reader, stdout, stderr io.Writer) int64) *fakeContainer {
+	t.Helper()
+	return &fakeContainer{
+		t:       t,
+		process: process,
+		exit:    make(chan container.WaitResponse, 1),
+	}
+}
+
+func (f *fakeContainer) CreateContainer(_ context.Context, _ string, config container.Config, _ container.HostConfig, networks network.NetworkingConfig) (string, error) {
+	f.config = config
+	f.networks = networks
+	return "fake", nil
+}
+
+func (f *fakeContainer) ConnectNetwork(_ context.Context, networkName, _, _ string) error {
+	f.connected = append(f.connected, networkName)
+	return nil
+}
+
+func (f *fakeContainer) AttachContainer(context.Context, string) (types.HijackedResponse, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(f.t, err)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(f.t, err)
+	f.server = <-accepted
+
+	return types.NewHijackedResponse(conn, ""), nil
+}
+
+func (f *fakeContainer) WaitContainer(context.Context, string, container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	return f.exit, make(chan error)
+}
+
+func (f *fakeContainer) StartCreatedContainer(context.Context, string) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+
+	go func() {
+		defer f.server.Close()
+
+		stdout := stdcopy.NewStdWriter(f.server, stdcopy.Stdout)
+		stderr := stdcopy.NewStdWriter(f.server, stdcopy.Stderr)
+		code := f.process(bufio.NewReader(f.server), stdout, stderr)
+		f.exit <- container.WaitResponse{StatusCode: code}
+	}()
+
+	return nil
+}
+
+func (f *fakeContainer) RemoveContainer(context.Context, string, bool) error {
+	f.removed = true
+	if f.server != nil {
+		_ = f.server.Close()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"encoding/json"
+)
+
+// argumentsMap converts the arguments of a tool call into a map.
+// On the server side, the SDK keeps the arguments as raw JSON.
+func argumentsMap(arguments any) (map[string]any, error) {
+	switch args := arguments.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return args, nil
+	case json.RawMessage:
+		if len(args) == 0 {
+			return map[string]any{}, nil
+		}
+	}
+
+	buf, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = map[string]any{}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/audit"
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+func (g *Gateway) openAuditLog() error {
+	if g.AuditLog == "" {
+		return nil
+	}
+
+	path, err := config.FilePath(g.AuditLog)
+	if err != nil {
+		return err
+	}
+
+	logger, err := audit.Open(path, int64(g.AuditLogMaxSize)*1024*1024, g.AuditLogMaxBackups)
+	if err != nil {
+		return err
+	}
+
+	log("- Audit log enabled:", path)
+	g.audit = logger
+	return nil
+}
+
+func (g *Gateway) auditToolHandler(serverName string, handler mcp.ToolHandler) mcp.ToolHandler {
+	if g.audit == nil {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, req)
+
+		record := g.newAuditRecord(audit.OperationToolCall, serverName, req.Session, start, req.Params.Arguments, result, err)
+		record.Tool = req.Params.Name
+		if err == nil && result != nil && result.IsError {
+			record.Status = audit.StatusError
+		}
+		g.writeAuditRecord(record)
+
+		return result, err
+	}
+}
+
+func (g *Gateway) auditPromptHandler(serverName string, handler mcp.PromptHandler) mcp.PromptHandler {
+	if g.audit == nil {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, req)
+
+		record := g.newAuditRecord(audit.OperationPromptGet, serverName, req.Session, start, req.Params.Arguments, result, err)
+		record.Prompt = req.Params.Name
+		g.writeAuditRecord(record)
+
+		return result, err
+	}
+}
+
+func (g *Gateway) auditResourceHandler(serverName string, handler mcp.ResourceHandler) mcp.ResourceHandler {
+	if g.audit == nil {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, req)
+
+		record := g.newAuditRecord(audit.OperationResourceRead, serverName, req.Session, start, nil, result, err)
+		record.Resource = req.Params.URI
+		g.writeAuditRecord(record)
+
+		return result, err
+	}
+}
+
+func (g *Gateway) newAuditRecord(operation, serverName string, ss *mcp.ServerSession, start time.Time, arguments, result any, err error) audit.Record {
+	record := audit.Record{
+		Timestamp:  start.UTC(),
+		Operation:  operation,
+		Server:     serverName,
+		Status:     audit.StatusSuccess,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if ss != nil {
+		record.Session = sessionID(ss)
+		if params := ss.InitializeParams(); params != nil && params.ClientInfo != nil {
+			record.Client = params.ClientInfo.Name
+			record.ClientVersion = params.ClientInfo.Version
+		}
+	}
+
+	if arguments != nil {
+		if buf, err := json.Marshal(arguments); err == nil && string(buf) != "null" {
+			record.RequestBytes = len(buf)
+		}
+		record.ArgumentsDigest = audit.Digest(arguments)
+		if g.AuditArguments == audit.ArgumentsRedacted {
+			if args, err := argumentsMap(arguments); err == nil && len(args) > 0 {
+				record.Arguments = audit.Redact(args)
+			}
+		}
+	}
+
+	if err != nil {
+		record.Status = audit.StatusFailure
+		record.Error = err.Error()
+	} else if buf, err := json.Marshal(result); err == nil {
+		record.ResponseBytes = len(buf)
+	}
+
+	return record
+}
+
+func (g *Gateway) writeAuditRecord(record audit.Record) {
+	if err := g.audit.Log(record); err != nil {
+		logf("! Unable to write to the audit log: %s", err)
+	}
+}
+
+// sessionID identifies a client session. Sessions over stdio have no id
+// so we fall back to something that is unique for the lifetime of the gateway.
+func sessionID(ss *mcp.ServerSession) string {
+	if id := ss.ID(); id != "" {
+		return id
+	}
+	return fmt.Sprintf("%p", ss)
+}
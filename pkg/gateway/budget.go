@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/audit"
+	"github.com/docker/mcp-gateway/pkg/budget"
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+// enforceToolsBudget trims the tools so that the tools list fits in the
+// --tools-budget. The least used tools are found in the audit log, over the
+// last --tools-budget-window.
+func (g *Gateway) enforceToolsBudget(tools []ToolRegistration) []ToolRegistration {
+	if g.ToolsBudget <= 0 {
+		return tools
+	}
+
+	calls := g.toolCalls()
+
+	candidates := make([]budget.Tool, len(tools))
+	handlers := map[string]ToolRegistration{}
+	for i, tool := range tools {
+		key := tool.ServerName + "/" + tool.Tool.Name
+		candidates[i] = budget.Tool{Server: tool.ServerName, Tool: tool.Tool, Calls: calls[tool.ServerName+"/"+auditedToolName(tool)]}
+		handlers[key] = tool
+	}
+
+	result := budget.Enforce(candidates, g.ToolsBudget, g.ToolsBudgetStrategies)
+	log(fmt.Sprintf("- Tools budget: %d tokens, down from %d, for a budget of %d", result.After, result.Before, g.ToolsBudget))
+	for _, hidden := range result.Hidden {
+		logf("  > Hiding %s/%s (%d calls)", hidden.Server, hidden.Tool.Name, hidden.Calls)
+	}
+	if result.After > g.ToolsBudget {
+		log("  > Can't fit the tools in the budget with strategies", g.ToolsBudgetStrategies)
+	}
+
+	kept := make([]ToolRegistration, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		registration := handlers[tool.Server+"/"+tool.Tool.Name]
+		registration.Tool = tool.Tool
+		kept = append(kept, registration)
+	}
+
+	return kept
+}
+
+// auditedToolName is the name under which the calls to a tool are audited:
+// the name of the tool on its server, rather than the name it was renamed to.
+// Virtual tools are audited under their own name.
+func auditedToolName(tool ToolRegistration) string {
+	if tool.UpstreamName != "" {
+		return tool.UpstreamName
+	}
+	return tool.Tool.Name
+}
+
+// toolCalls counts the recent calls to each tool, as recorded in the audit log.
+func (g *Gateway) toolCalls() map[string]int {
+	calls := map[string]int{}
+	if g.AuditLog == "" {
+		return calls
+	}
+
+	path, err := config.FilePath(g.AuditLog)
+	if err != nil {
+		return calls
+	}
+
+	filter := audit.Filter{Operation: audit.OperationToolCall}
+	if g.ToolsBudgetWindow > 0 {
+		filter.Since = time.Now().Add(-g.ToolsBudgetWindow)
+	}
+
+	records, err := audit.Read(path, filter)
+	if err != nil {
+		logf("  > Can't read the tool calls from %s: %s", path, err)
+		return calls
+	}
+
+	for _, record := range records {
+		calls[record.Server+"/"+record.Tool]++
+	}
+	return calls
+}
+
+// withServerMeta advertises, in the _meta of a tool, which server provides it.
+// Clients, like `docker mcp tools budget`, use it to group tools by server.
+// A value set by the server itself is kept.
+func withServerMeta(tool ToolRegistration) *mcp.Tool {
+	if tool.ServerName == "" {
+		return tool.Tool
+	}
+	if _, found := tool.Tool.Meta[budget.ServerMetaKey]; found {
+		return tool.Tool
+	}
+
+	withMeta := *tool.Tool
+	withMeta.Meta = maps.Clone(tool.Tool.Meta)
+	if withMeta.Meta == nil {
+		withMeta.Meta = mcp.Meta{}
+	}
+	withMeta.Meta[budget.ServerMetaKey] = tool.ServerName
+	return &withMeta
+}
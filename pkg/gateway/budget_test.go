@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/audit"
+	"github.com/docker/mcp-gateway/pkg/budget"
+)
+
+func TestEnforceToolsBudget(t *testing.T) {
+	auditLog := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(auditLog, 0, 0)
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, logger.Log(audit.Record{Timestamp: time.Now(), Operation: audit.OperationToolCall, Server: "github", Tool: "get_issue"}))
+	}
+	require.NoError(t, logger.Close())
+
+	description := strings.Repeat("Does many things. ", 50)
+	tools := []ToolRegistration{
+		{ServerName: "github", Tool: &mcp.Tool{Name: "get_issue", Description: description, InputSchema: &jsonschema.Schema{Type: "object"}}},
+		{ServerName: "github", Tool: &mcp.Tool{Name: "create_issue", Description: description, InputSchema: &jsonschema.Schema{Type: "object"}}},
+	}
+
+	g := &Gateway{Options: Options{
+		AuditLog:              auditLog,
+		ToolsBudget:           budget.Estimate(&mcp.Tool{Name: "get_issue", Description: "Does many things.", InputSchema: &jsonschema.Schema{Type: "object"}}),
+		ToolsBudgetStrategies: []string{budget.StrategyDescriptions, budget.StrategyHide},
+	}}
+	kept := g.enforceToolsBudget(tools)
+
+	require.Len(t, kept, 1)
+	assert.Equal(t, "get_issue", kept[0].Tool.Name)
+	assert.Equal(t, "github", kept[0].ServerName)
+	assert.Equal(t, "Does many things.", kept[0].Tool.Description)
+	assert.Equal(t, description, tools[0].Tool.Description)
+}
+
+func TestEnforceToolsBudgetRecentUpstreamCalls(t *testing.T) {
+	auditLog := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(auditLog, 0, 0)
+	require.NoError(t, err)
+	// Calls are audited under the name of the tool on its server.
+	for range 3 {
+		require.NoError(t, logger.Log(audit.Record{Timestamp: time.Now(), Operation: audit.OperationToolCall, Server: "github", Tool: "get_issue"}))
+	}
+	// Those calls are too old to count.
+	for range 5 {
+		require.NoError(t, logger.Log(audit.Record{Timestamp: time.Now().Add(-10 * 24 * time.Hour), Operation: audit.OperationToolCall, Server: "github", Tool: "create_issue"}))
+	}
+	require.NoError(t, logger.Close())
+
+	tools := []ToolRegistration{
+		{ServerName: "github", UpstreamName: "get_issue", Tool: &mcp.Tool{Name: "gh_get_issue", InputSchema: &jsonschema.Schema{Type: "object"}}},
+		{ServerName: "github", UpstreamName: "create_issue", Tool: &mcp.Tool{Name: "create_issue", InputSchema: &jsonschema.Schema{Type: "object"}}},
+	}
+
+	g := &Gateway{Options: Options{
+		AuditLog:              auditLog,
+		ToolsBudget:           budget.Estimate(tools[0].Tool),
+		ToolsBudgetStrategies: []string{budget.StrategyHide},
+		ToolsBudgetWindow:     7 * 24 * time.Hour,
+	}}
+	kept := g.enforceToolsBudget(tools)
+
+	require.Len(t, kept, 1)
+	assert.Equal(t, "gh_get_issue", kept[0].Tool.Name)
+}
+
+func TestWithServerMeta(t *testing.T) {
+	tool := &mcp.Tool{Name: "get_issue", Meta: mcp.Meta{"other": "value"}}
+
+	withMeta := withServerMeta(ToolRegistration{ServerName: "github", Tool: tool})
+
+	assert.Equal(t, mcp.Meta{"other": "value", budget.ServerMetaKey: "github"}, withMeta.Meta)
+	assert.Equal(t, mcp.Meta{"other": "value"}, tool.Meta)
+	assert.Equal(t, budget.Estimate(tool), budget.Estimate(withMeta))
+	assert.Same(t, tool, withServerMeta(ToolRegistration{Tool: tool}))
+
+	upstream := &mcp.Tool{Name: "get_issue", Meta: mcp.Meta{budget.ServerMetaKey: "upstream"}}
+	assert.Equal(t, mcp.Meta{budget.ServerMetaKey: "upstream"}, withServerMeta(ToolRegistration{ServerName: "github", Tool: upstream}).Meta)
+}
+
+func TestEnforceToolsBudgetDisabled(t *testing.T) {
+	tools := []ToolRegistration{{ServerName: "github", Tool: &mcp.Tool{Name: "get_issue", Description: "Get an issue"}}}
+
+	g := &Gateway{}
+
+	assert.Equal(t, tools, g.enforceToolsBudget(tools))
+}
@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+func TestIsPromptEnabled(t *testing.T) {
+	configuration := Configuration{
+		prompts: config.FilterConfig{Servers: map[string][]string{
+			"github": {"review"},
+			"notion": {"search"},
+		}},
+	}
+
+	assert.True(t, isPromptEnabled(configuration, "github", "", "review", nil))
+	assert.False(t, isPromptEnabled(configuration, "github", "", "summarize", nil))
+	assert.True(t, isPromptEnabled(configuration, "notion", "", "search", nil))
+	assert.False(t, isPromptEnabled(configuration, "notion", "", "write", nil))
+	assert.True(t, isPromptEnabled(configuration, "other", "", "write", nil))
+
+	// The command line takes precedence over the prompts files.
+	assert.True(t, isPromptEnabled(configuration, "github", "", "summarize", []string{"github:*"}))
+	assert.False(t, isPromptEnabled(configuration, "github", "", "review", []string{"summarize"}))
+	assert.True(t, isPromptEnabled(configuration, "github", "mcp/github", "review", []string{"mcp/github:review"}))
+}
+
+func TestIsResourceEnabled(t *testing.T) {
+	configuration := Configuration{
+		resources: config.FilterConfig{Servers: map[string][]string{
+			"github": {"repo://docker/mcp-gateway/LICENSE"},
+		}},
+	}
+
+	assert.False(t, isResourceEnabled(configuration, "github", "", "repo://{owner}/{repo}/contents{/path*}", "contents", nil))
+	assert.False(t, isResourceEnabled(configuration, "github", "", "repo://docker/mcp-gateway/README.md", "readme", nil))
+	assert.True(t, isResourceEnabled(configuration, "github", "", "repo://docker/mcp-gateway/LICENSE", "license", nil))
+	assert.True(t, isResourceEnabled(configuration, "notion", "", "notion://page", "page", nil))
+
+	assert.True(t, isResourceEnabled(configuration, "github", "", "repo://docker/mcp-gateway/README.md", "readme", []string{"github:readme"}))
+	assert.True(t, isResourceEnabled(configuration, "github", "", "repo://docker/mcp-gateway/README.md", "readme", []string{"repo://docker/mcp-gateway/README.md"}))
+	assert.False(t, isResourceEnabled(configuration, "github", "", "repo://docker/mcp-gateway/LICENSE", "license", []string{"notion:*"}))
+}
+
+func TestIsToolEnabled(t *testing.T) {
+	configuration := Configuration{
+		tools: config.ToolsConfig{ServerTools: map[string][]string{"github": {"get_issue"}}},
+	}
+
+	assert.True(t, isToolEnabled(configuration, "github", "", "get_issue", nil))
+	assert.False(t, isToolEnabled(configuration, "github", "", "create_issue", nil))
+	assert.True(t, isToolEnabled(configuration, "notion", "", "search", nil))
+	assert.True(t, isToolEnabled(configuration, "github", "mcp/github", "create_issue", []string{"mcp/github:*"}))
+	assert.True(t, isToolEnabled(configuration, "github", "", "create_issue", []string{"*"}))
+	assert.False(t, isToolEnabled(configuration, "github", "", "create_issue", []string{"notion:*"}))
+}
@@ -8,7 +8,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"golang.org/x/sync/errgroup"
 
@@ -23,8 +22,14 @@ type Capabilities struct {
 }
 
 type ToolRegistration struct {
-	Tool    *mcp.Tool
-	Handler mcp.ToolHandler
+	ServerName string
+	// UpstreamName is the name of the tool on its server. It differs from the
+	// name of the Tool when the tool is renamed by an override.
+	UpstreamName string
+	Tool         *mcp.Tool
+	Handler      mcp.ToolHandler
+	// steps are the tools a virtual tool calls.
+	steps []ToolRegistration
 }
 
 type PromptRegistration struct {
@@ -80,9 +85,19 @@ func (g *Gateway) listCapabilities(ctx context.Context, configuration Configurat
 						if !isToolEnabled(configuration, serverConfig.Name, serverConfig.Spec.Image, tool.Name, g.ToolNames) {
 							continue
 						}
+						if tool.OutputSchema != nil && tool.OutputSchema.Type != "object" {
+							// The MCP spec requires an object, and the SDK refuses anything else.
+							logf("  > Ignoring invalid output schema of %s/%s", serverConfig.Name, tool.Name)
+							tool.OutputSchema = nil
+						}
+						exposedName := g.exposedToolName(serverConfig.Name, tool)
+						handler := g.auditToolHandler(serverConfig.Name, g.policyToolHandler(serverConfig.Name, exposedName, g.confirmToolHandler(serverConfig.Name, exposedName, tool.Annotations, g.validateToolHandler(tool, g.outputToolHandler(serverConfig.Name, tool, g.mcpServerToolHandler(serverConfig, g.mcpServer, tool.Annotations))))))
+						overridden, handler := g.overrideTool(serverConfig.Name, serverConfig.Config, tool, handler)
 						capabilities.Tools = append(capabilities.Tools, ToolRegistration{
-							Tool:    tool,
-							Handler: g.mcpServerToolHandler(serverConfig, g.mcpServer, tool.Annotations),
+							ServerName:   serverConfig.Name,
+							UpstreamName: tool.Name,
+							Tool:         overridden,
+							Handler:      handler,
 						})
 					}
 				}
@@ -93,9 +108,12 @@ func (g *Gateway) listCapabilities(ctx context.Context, configuration Configurat
 					telemetry.RecordPromptList(ctx, serverConfig.Name, len(prompts.Prompts))
 
 					for _, prompt := range prompts.Prompts {
+						if !isPromptEnabled(configuration, serverConfig.Name, serverConfig.Spec.Image, prompt.Name, g.PromptNames) {
+							continue
+						}
 						capabilities.Prompts = append(capabilities.Prompts, PromptRegistration{
 							Prompt:  prompt,
-							Handler: g.mcpServerPromptHandler(serverConfig, g.mcpServer),
+							Handler: g.auditPromptHandler(serverConfig.Name, g.mcpServerPromptHandler(serverConfig, g.mcpServer)),
 						})
 					}
 				}
@@ -106,9 +124,12 @@ func (g *Gateway) listCapabilities(ctx context.Context, configuration Configurat
 					telemetry.RecordResourceList(ctx, serverConfig.Name, len(resources.Resources))
 
 					for _, resource := range resources.Resources {
+						if !isResourceEnabled(configuration, serverConfig.Name, serverConfig.Spec.Image, resource.URI, resource.Name, g.ResourceNames) {
+							continue
+						}
 						capabilities.Resources = append(capabilities.Resources, ResourceRegistration{
 							Resource: resource,
-							Handler:  g.mcpServerResourceHandler(serverConfig, g.mcpServer),
+							Handler:  g.auditResourceHandler(serverConfig.Name, g.mcpServerResourceHandler(serverConfig, g.mcpServer)),
 						})
 					}
 				}
@@ -119,9 +140,12 @@ func (g *Gateway) listCapabilities(ctx context.Context, configuration Configurat
 					telemetry.RecordResourceTemplateList(ctx, serverConfig.Name, len(resourceTemplates.ResourceTemplates))
 
 					for _, resourceTemplate := range resourceTemplates.ResourceTemplates {
+						if !isResourceEnabled(configuration, serverConfig.Name, serverConfig.Spec.Image, resourceTemplate.URITemplate, resourceTemplate.Name, g.ResourceNames) {
+							continue
+						}
 						capabilities.ResourceTemplates = append(capabilities.ResourceTemplates, ResourceTemplateRegistration{
 							ResourceTemplate: *resourceTemplate,
-							Handler:          g.mcpServerResourceHandler(serverConfig, g.mcpServer),
+							Handler:          g.auditResourceHandler(serverConfig.Name, g.mcpServerResourceHandler(serverConfig, g.mcpServer)),
 						})
 					}
 				}
@@ -159,25 +183,25 @@ func (g *Gateway) listCapabilities(ctx context.Context, configuration Configurat
 					continue
 				}
 
+				inputSchema, err := tool.Parameters.Schema()
+				if err != nil {
+					logf("  > Can't convert the parameters of %s: %s", tool.Name, err)
+					continue
+				}
 				mcpTool := mcp.Tool{
 					Name:        tool.Name,
 					Description: tool.Description,
-					InputSchema: &jsonschema.Schema{},
-				}
-				// TODO: Properly convert tool.Parameters to jsonschema.Schema
-				// For now, we'll create a simple schema structure
-				if len(tool.Parameters.Properties) == 0 {
-					mcpTool.InputSchema.Type = "object"
-				} else {
-					mcpTool.InputSchema.Type = tool.Parameters.Type
-					// Note: tool.Parameters.Properties.ToMap() returns map[string]any
-					// but we need map[string]*jsonschema.Schema
-					// This is a complex conversion that needs proper implementation
+					InputSchema: inputSchema,
 				}
 
+				exposedName := g.exposedToolName(serverName, &mcpTool)
+				handler := g.auditToolHandler(serverName, g.policyToolHandler(serverName, exposedName, g.confirmToolHandler(serverName, exposedName, nil, g.validateToolHandler(&mcpTool, g.mcpToolHandler(tool, configuration.secrets)))))
+				overridden, handler := g.overrideTool(serverName, nil, &mcpTool, handler)
 				capabilities.Tools = append(capabilities.Tools, ToolRegistration{
-					Tool:    &mcpTool,
-					Handler: g.mcpToolHandler(tool),
+					ServerName:   serverName,
+					UpstreamName: mcpTool.Name,
+					Tool:         overridden,
+					Handler:      handler,
 				})
 			}
 
@@ -237,23 +261,52 @@ func isToolEnabled(configuration Configuration, serverName, serverImage, toolNam
 		return slices.Contains(tools, toolName)
 	}
 
-	for _, enabled := range enabledTools {
-		if enabled == "*" ||
-			strings.EqualFold(enabled, toolName) ||
-			strings.EqualFold(enabled, serverName+":"+toolName) ||
-			strings.EqualFold(enabled, serverName+":*") ||
-			strings.EqualFold(enabled, "*") {
-			return true
-		}
+	return isNameEnabled(serverName, serverImage, enabledTools, toolName)
+}
+
+func isPromptEnabled(configuration Configuration, serverName, serverImage, promptName string, enabledPrompts []string) bool {
+	if len(enabledPrompts) == 0 {
+		return configuration.prompts.IsEnabled(serverName, promptName)
 	}
 
-	if serverImage != "" {
-		for _, enabled := range enabledTools {
-			if strings.EqualFold(enabled, serverImage+":"+toolName) ||
-				strings.EqualFold(enabled, serverImage+":*") {
+	return isNameEnabled(serverName, serverImage, enabledPrompts, promptName)
+}
+
+// isResourceEnabled applies to resources, identified by their URI or their name,
+// and to resource templates, identified by their URI template or their name.
+func isResourceEnabled(configuration Configuration, serverName, serverImage, uri, resourceName string, enabledResources []string) bool {
+	if len(enabledResources) == 0 {
+		return configuration.resources.IsEnabled(serverName, uri, resourceName)
+	}
+
+	return isNameEnabled(serverName, serverImage, enabledResources, uri, resourceName)
+}
+
+// isNameEnabled matches names against a list given on the command line, where
+// each entry is either `name`, `server:name`, `server:*`, `image:name`, `image:*` or `*`.
+func isNameEnabled(serverName, serverImage string, enabled []string, names ...string) bool {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		for _, enabled := range enabled {
+			if enabled == "*" ||
+				strings.EqualFold(enabled, name) ||
+				strings.EqualFold(enabled, serverName+":"+name) ||
+				strings.EqualFold(enabled, serverName+":*") {
 				return true
 			}
 		}
+
+		if serverImage != "" {
+			for _, enabled := range enabled {
+				if strings.EqualFold(enabled, serverImage+":"+name) ||
+					strings.EqualFold(enabled, serverImage+":*") {
+					return true
+				}
+			}
+		}
 	}
 
 	return false
@@ -1,20 +1,32 @@
 package gateway
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-units"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/eval"
 	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
 	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+	"github.com/docker/mcp-gateway/pkg/mounts"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
 type clientKey struct {
@@ -36,6 +48,12 @@ type clientPool struct {
 	networks    []string
 	docker      docker.Client
 	gateway     *Gateway
+	mountPolicy *mounts.Policy
+
+	// What was last recorded about each server, so that servers.json is only
+	// read and written when that changes.
+	serverInfoLock sync.Mutex
+	serverInfo     map[string]config.ServerInfo
 }
 
 type clientConfig struct {
@@ -49,6 +67,7 @@ func newClientPool(options Options, docker docker.Client, gateway *Gateway) *cli
 		Options:     options,
 		docker:      docker,
 		gateway:     gateway,
+		mountPolicy: mounts.DefaultPolicy(),
 		keptClients: make(map[clientKey]keptClient),
 	}
 }
@@ -61,7 +80,7 @@ func (cp *clientPool) UpdateRoots(ss *mcp.ServerSession, roots []*mcp.Root) {
 		if kc.ClientConfig != nil && (kc.ClientConfig.serverSession == ss) {
 			client, err := kc.Getter.GetClient(context.TODO()) // should be cached
 			if err == nil {
-				client.AddRoots(roots)
+				client.AddRoots(kc.Getter.serverRoots(roots))
 			}
 		}
 	}
@@ -202,19 +221,144 @@ func (cp *clientPool) InvalidateOAuthClients(provider string) {
 	}
 }
 
-func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
-	args := cp.baseArgs(tool.Name)
-
-	// Attach the MCP servers to the same network as the gateway.
-	for _, network := range cp.networks {
-		args = append(args, "--network", network)
-	}
-
+func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, secrets map[string]string, params *mcp.CallToolParams, progress *toolProgress) (*mcp.CallToolResult, error) {
 	// Convert params.Arguments to map[string]any
 	arguments, ok := params.Arguments.(map[string]any)
 	if !ok {
 		arguments = make(map[string]any)
 	}
+	arguments = tool.Parameters.WithDefaults(arguments)
+
+	// Network
+	var targetConfig proxies.TargetConfig
+	if cp.BlockNetwork && len(tool.Container.AllowHosts) > 0 && !tool.Container.DisableNetwork {
+		var cleanup func(context.Context) error
+		var err error
+		if targetConfig, cleanup, err = cp.runProxies(ctx, tool.Container.AllowHosts, false); err != nil {
+			return nil, err
+		}
+		defer func() { _ = cleanup(context.WithoutCancel(ctx)) }()
+	}
+
+	spec, err := cp.toolSpec(tool, secrets, arguments, targetConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stdin
+	var stdin io.Reader
+	if tool.Container.Stdin != "" {
+		content, err := stdinContent(eval.Evaluate(tool.Container.Stdin, arguments))
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: invalid stdin: %w", tool.Name, err)
+		}
+		stdin = strings.NewReader(content)
+	}
+
+	// Timeout
+	if tool.Container.Timeout != "" {
+		timeout, err := time.ParseDuration(tool.Container.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: invalid timeout: %w", tool.Name, err)
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, errToolTimeout)
+		defer cancel()
+	}
+
+	log("  - Running container", tool.Container.Image, "with command", spec.Config.Cmd)
+
+	// Stream the output to the client, if it asked for progress, while
+	// collecting it for the result.
+	var out, errOut bytes.Buffer
+	stdoutProgress := progress.writer("stdout")
+	stderrProgress := progress.writer("stderr")
+	stdout := io.MultiWriter(&out, stdoutProgress)
+	stderr := io.MultiWriter(&errOut, stderrProgress)
+	if cp.Verbose {
+		stderr = io.MultiWriter(stderr, os.Stderr)
+	}
+
+	err = docker.RunContainer(ctx, cp.docker, spec, stdin, stdout, stderr)
+	stdoutProgress.Flush()
+	stderrProgress.Flush()
+
+	if err != nil {
+		if errors.Is(context.Cause(ctx), errToolTimeout) {
+			return toolResult(fmt.Sprintf("%s timed out after %s\n%s", tool.Name, tool.Container.Timeout, out.String()), errOut.String(), nil), nil
+		}
+
+		var exitErr *docker.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("running container %s: %w", tool.Container.Image, err)
+		}
+
+		return toolResult(out.String(), errOut.String(), &exitErr.Code), nil
+	}
+
+	var exitCode int64
+	return toolResult(out.String(), "", &exitCode), nil
+}
+
+// toolResult is the result of a POCI tool. Its exit code, when known, is in
+// the metadata. On failure, stderr is attached as a separate content.
+func toolResult(stdout, stderr string, exitCode *int64) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{
+			Text: stdout,
+		}},
+		IsError: exitCode == nil || *exitCode != 0,
+	}
+
+	if exitCode != nil {
+		result.Meta = mcp.Meta{"exitCode": *exitCode}
+	}
+	if result.IsError && stderr != "" {
+		result.Content = append(result.Content, &mcp.TextContent{
+			Text: stderr,
+		})
+	}
+
+	return result
+}
+
+// toolSpec is the container that runs a POCI tool, for the given arguments.
+func (cp *clientPool) toolSpec(tool catalog.Tool, secrets map[string]string, arguments map[string]any, targetConfig proxies.TargetConfig) (docker.ContainerSpec, error) {
+	spec, err := cp.baseSpec(tool.Name)
+	if err != nil {
+		return docker.ContainerSpec{}, err
+	}
+
+	cp.withNetwork(&spec, tool.Container.DisableNetwork, targetConfig)
+	env := slices.Clone(targetConfig.Env)
+
+	// Secrets
+	for _, s := range tool.Container.Secrets {
+		secretValue, ok := secrets[s.Name]
+		if !ok {
+			logf("Warning: Secret '%s' not found for tool '%s', setting %s=<UNKNOWN>. To fix: docker mcp secret set %s=<value>", s.Name, tool.Name, s.Env, s.Name)
+			secretValue = "<UNKNOWN>"
+		}
+		env = append(env, fmt.Sprintf("%s=%s", s.Env, secretValue))
+	}
+
+	// Env
+	for _, e := range tool.Container.Env {
+		var value string
+		if strings.Contains(e.Value, "{{") && strings.Contains(e.Value, "}}") {
+			if evaluated := eval.Evaluate(e.Value, arguments); evaluated != nil {
+				value = fmt.Sprintf("%v", evaluated)
+			}
+		} else {
+			value = expandEnv(e.Value, env)
+		}
+
+		if value != "" {
+			env = append(env, fmt.Sprintf("%s=%s", e.Name, value))
+		}
+	}
+	spec.Config.Env = env
 
 	// Volumes
 	for _, mount := range eval.EvaluateList(tool.Container.Volumes, arguments) {
@@ -222,105 +366,208 @@ func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, p
 			continue
 		}
 
-		args = append(args, "-v", mount)
+		mount, err := cp.checkMount(mount)
+		if err != nil {
+			return docker.ContainerSpec{}, err
+		}
+		spec.HostConfig.Binds = append(spec.HostConfig.Binds, mount)
 	}
 
 	// User
 	if tool.Container.User != "" {
 		userVal := fmt.Sprintf("%v", eval.Evaluate(tool.Container.User, arguments))
 		if userVal != "" {
-			args = append(args, "-u", userVal)
+			spec.Config.User = userVal
 		}
 	}
 
-	// Image
-	args = append(args, tool.Container.Image)
+	// Working directory
+	if tool.Container.WorkingDir != "" {
+		spec.Config.WorkingDir = fmt.Sprintf("%v", eval.Evaluate(tool.Container.WorkingDir, arguments))
+	}
 
-	// Command
-	command := eval.EvaluateList(tool.Container.Command, arguments)
-	args = append(args, command...)
+	// Resources
+	if err := cp.applyResources(&spec, tool.Container.Resources); err != nil {
+		return docker.ContainerSpec{}, fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
 
-	log("  - Running container", tool.Container.Image, "with args", args)
+	// Image and command
+	spec.Config.Image = tool.Container.Image
+	spec.Config.Cmd = eval.EvaluateList(tool.Container.Command, arguments)
 
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	if cp.Verbose {
-		cmd.Stderr = os.Stderr
-	}
-	out, err := cmd.Output()
-	if err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{
-				Text: string(out),
-			}},
-			IsError: true,
-		}, nil
-	}
+	return spec, nil
+}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{
-			Text: string(out),
-		}},
-		IsError: false,
-	}, nil
+var errToolTimeout = errors.New("tool timed out")
+
+// stdinContent is what's written to the stdin of a tool: strings as is and
+// anything else as JSON.
+func stdinContent(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		buf, err := json.Marshal(v)
+		return string(buf), err
+	}
 }
 
-func (cp *clientPool) baseArgs(name string) []string {
-	args := []string{"run"}
+// baseSpec is the equivalent of `docker run --rm -i --init` with the options
+// shared by every container the gateway runs.
+func (cp *clientPool) baseSpec(name string) (docker.ContainerSpec, error) {
+	useInit := true
+	spec := docker.ContainerSpec{
+		Config: container.Config{
+			OpenStdin: true,
+			StdinOnce: true,
+			// Add a few labels to the container for identification
+			Labels: map[string]string{
+				"docker-mcp":           "true",
+				"docker-mcp-tool-type": "mcp",
+				"docker-mcp-name":      name,
+				"docker-mcp-transport": "stdio",
+			},
+		},
+		HostConfig: container.HostConfig{
+			AutoRemove:  true,
+			Init:        &useInit,
+			SecurityOpt: []string{"no-new-privileges"},
+		},
+	}
 
-	args = append(args, "--rm", "-i", "--init", "--security-opt", "no-new-privileges")
 	if cp.Cpus > 0 {
-		args = append(args, "--cpus", fmt.Sprintf("%d", cp.Cpus))
+		spec.HostConfig.NanoCPUs = int64(cp.Cpus) * 1e9
 	}
 	if cp.Memory != "" {
-		args = append(args, "--memory", cp.Memory)
+		memory, err := units.RAMInBytes(cp.Memory)
+		if err != nil {
+			return docker.ContainerSpec{}, fmt.Errorf("invalid memory limit %q: %w", cp.Memory, err)
+		}
+		spec.HostConfig.Memory = memory
 	}
-	args = append(args, "--pull", "never")
 
 	if os.Getenv("DOCKER_MCP_IN_DIND") == "1" {
-		args = append(args, "--privileged")
+		spec.HostConfig.Privileged = true
 	}
 
-	// Add a few labels to the container for identification
-	args = append(args,
-		"-l", "docker-mcp=true",
-		"-l", "docker-mcp-tool-type=mcp",
-		"-l", "docker-mcp-name="+name,
-		"-l", "docker-mcp-transport=stdio",
-	)
+	return spec, nil
+}
+
+// withNetwork attaches a container to the same networks as the gateway, or to
+// no network at all, and to the proxies that control its network access.
+func (cp *clientPool) withNetwork(spec *docker.ContainerSpec, disableNetwork bool, targetConfig proxies.TargetConfig) {
+	var networks []string
+	if disableNetwork {
+		networks = append(networks, "none")
+	} else {
+		// Attach the MCP servers to the same network as the gateway.
+		networks = append(networks, cp.networks...)
+	}
+	if targetConfig.NetworkName != "" {
+		networks = append(networks, targetConfig.NetworkName)
+	}
+	withNetworks(spec, networks)
+	spec.HostConfig.Links = append(spec.HostConfig.Links, targetConfig.Links...)
+	if targetConfig.DNS != "" {
+		spec.HostConfig.DNS = append(spec.HostConfig.DNS, targetConfig.DNS)
+	}
+}
 
-	return args
+// withNetworks connects the container to the given networks, the first one
+// being its network mode.
+func withNetworks(spec *docker.ContainerSpec, networks []string) {
+	for _, name := range networks {
+		if spec.HostConfig.NetworkMode == "" {
+			spec.HostConfig.NetworkMode = container.NetworkMode(name)
+		}
+		if spec.NetworkingConfig.EndpointsConfig == nil {
+			spec.NetworkingConfig.EndpointsConfig = map[string]*network.EndpointSettings{}
+		}
+		spec.NetworkingConfig.EndpointsConfig[name] = &network.EndpointSettings{}
+	}
 }
 
-func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *bool, targetConfig proxies.TargetConfig) ([]string, []string) {
-	args := cp.baseArgs(serverConfig.Name)
-	var env []string
+func (cp *clientPool) containerSpec(ctx context.Context, serverConfig *catalog.ServerConfig, readOnly *bool, targetConfig proxies.TargetConfig) (docker.ContainerSpec, error) {
+	spec, err := cp.baseSpec(serverConfig.Name)
+	if err != nil {
+		return docker.ContainerSpec{}, err
+	}
 
 	// Security options
-	if serverConfig.Spec.DisableNetwork {
-		args = append(args, "--network", "none")
-	} else {
-		// Attach the MCP servers to the same network as the gateway.
-		for _, network := range cp.networks {
-			args = append(args, "--network", network)
+	cp.withNetwork(&spec, serverConfig.Spec.DisableNetwork, targetConfig)
+	env := serverEnv(serverConfig, targetConfig.Env)
+	spec.Config.Env = env
+
+	// Volumes
+	for _, mount := range eval.EvaluateList(serverConfig.Spec.Volumes, serverConfig.Config) {
+		if mount == "" {
+			continue
+		}
+
+		mount, err := cp.checkMount(mount)
+		if err != nil {
+			return docker.ContainerSpec{}, fmt.Errorf("server %s: %w", serverConfig.Name, err)
+		}
+
+		if readOnly != nil && *readOnly && !strings.HasSuffix(mount, ":ro") {
+			spec.HostConfig.Binds = append(spec.HostConfig.Binds, mount+":ro")
+		} else {
+			spec.HostConfig.Binds = append(spec.HostConfig.Binds, mount)
 		}
 	}
-	if targetConfig.NetworkName != "" {
-		args = append(args, "--network", targetConfig.NetworkName)
+
+	// User
+	if serverConfig.Spec.User != "" {
+		val := serverConfig.Spec.User
+		if strings.Contains(val, "{{") && strings.Contains(val, "}}") {
+			val = fmt.Sprintf("%v", eval.Evaluate(val, serverConfig.Config))
+		}
+		if val != "" {
+			spec.Config.User = val
+		}
 	}
-	for _, link := range targetConfig.Links {
-		args = append(args, "--link", link)
+
+	// Image and command
+	spec.Config.Image = serverConfig.Spec.Image
+	spec.Config.Cmd = serverArgs(serverConfig, env)
+
+	// Package servers run in a generic runtime container
+	if serverConfig.Spec.Package != nil {
+		if err := cp.withPackage(&spec, serverConfig.Name, serverConfig.Spec.Package); err != nil {
+			return docker.ContainerSpec{}, fmt.Errorf("server %s: %w", serverConfig.Name, err)
+		}
 	}
-	for _, env := range targetConfig.Env {
-		args = append(args, "-e", env)
+
+	// Hardened security profile
+	profile := SecurityProfile(ctx, cp.docker, serverConfig.Spec, cp.SeccompProfile)
+	if err := profile.Apply(&spec.Config, &spec.HostConfig); err != nil {
+		return docker.ContainerSpec{}, err
 	}
-	if targetConfig.DNS != "" {
-		args = append(args, "--dns", targetConfig.DNS)
+	if profile.User != "" && spec.Config.User == profile.User {
+		logf("  - Running %s as user %s instead of root", serverConfig.Name, profile.User)
+	}
+
+	// Resources
+	resources, err := serverResources(serverConfig)
+	if err != nil {
+		return docker.ContainerSpec{}, err
+	}
+	if err := cp.applyResources(&spec, resources); err != nil {
+		return docker.ContainerSpec{}, err
 	}
 
+	return spec, nil
+}
+
+// serverEnv is the env of a server: the given env, followed by its secrets and
+// its env.
+func serverEnv(serverConfig *catalog.ServerConfig, env []string) []string {
+	env = slices.Clone(env)
+
 	// Secrets
 	for _, s := range serverConfig.Spec.Secrets {
-		args = append(args, "-e", s.Env)
-
 		secretValue, ok := serverConfig.Secrets[s.Name]
 		if ok {
 			env = append(env, fmt.Sprintf("%s=%s", s.Env, secretValue))
@@ -340,36 +587,26 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *b
 		}
 
 		if value != "" {
-			args = append(args, "-e", e.Name)
 			env = append(env, fmt.Sprintf("%s=%s", e.Name, value))
 		}
 	}
 
-	// Volumes
-	for _, mount := range eval.EvaluateList(serverConfig.Spec.Volumes, serverConfig.Config) {
-		if mount == "" {
-			continue
-		}
+	return env
+}
 
-		if readOnly != nil && *readOnly && !strings.HasSuffix(mount, ":ro") {
-			args = append(args, "-v", mount+":ro")
-		} else {
-			args = append(args, "-v", mount)
-		}
-	}
+// serverArgs is the command of a server, evaluated against its config and env.
+func serverArgs(serverConfig *catalog.ServerConfig, env []string) []string {
+	return expandEnvList(eval.EvaluateList(serverConfig.Spec.Command, serverConfig.Config), env)
+}
 
-	// User
-	if serverConfig.Spec.User != "" {
-		val := serverConfig.Spec.User
-		if strings.Contains(val, "{{") && strings.Contains(val, "}}") {
-			val = fmt.Sprintf("%v", eval.Evaluate(val, serverConfig.Config))
-		}
-		if val != "" {
-			args = append(args, "-u", val)
-		}
+// imageUser is the user an image runs as. If the image can't be inspected, it's
+// assumed to run as root.
+func imageUser(ctx context.Context, dockerClient docker.Client, image string) string {
+	inspect, err := dockerClient.InspectImage(ctx, image)
+	if err != nil || inspect.Config == nil {
+		return ""
 	}
-
-	return args, env
+	return inspect.Config.User
 }
 
 func expandEnv(value string, env []string) string {
@@ -400,6 +637,11 @@ type clientGetter struct {
 	cp           *clientPool
 
 	clientConfig *clientConfig
+
+	// Client roots mounted into the container, for servers that need workspace access.
+	workspace []workspaceMount
+	// Client roots when the container started. Roots added later can't be mounted.
+	workspaceRoots []*mcp.Root
 }
 
 func newClientGetter(serverConfig *catalog.ServerConfig, cp *clientPool, config *clientConfig) *clientGetter {
@@ -414,6 +656,63 @@ func (cg *clientGetter) IsClient(client mcpclient.Client) bool {
 	return cg.client == client
 }
 
+// serverRoots are the roots, as seen by the server.
+func (cg *clientGetter) serverRoots(roots []*mcp.Root) []*mcp.Root {
+	if !cg.serverConfig.Spec.Workspace {
+		return roots
+	}
+
+	for _, root := range addedRoots(cg.workspaceRoots, roots) {
+		logf("  - Not mounting root %s into %s: it was added after the server started, restart the server to use it", root.URI, cg.serverConfig.Name)
+	}
+	return containerRoots(cg.workspace, roots)
+}
+
+// recordServerInfo keeps track of the protocol version negotiated with each server.
+func (cp *clientPool) recordServerInfo(ctx context.Context, serverName string, result *mcp.InitializeResult) {
+	// Replayed sessions don't tell us anything new about the servers.
+	if result == nil || cp.ReplayDir != "" {
+		return
+	}
+
+	if cp.Verbose {
+		logf("  > %s: protocol version %s", serverName, result.ProtocolVersion)
+	}
+	telemetry.RecordServerInitialize(ctx, serverName, result.ProtocolVersion)
+
+	info := config.ServerInfo{
+		ProtocolVersion: result.ProtocolVersion,
+		Since:           time.Now(),
+	}
+	if result.ServerInfo != nil {
+		info.Name = result.ServerInfo.Name
+		info.Version = result.ServerInfo.Version
+	}
+
+	cp.serverInfoLock.Lock()
+	defer cp.serverInfoLock.Unlock()
+	if previous, found := cp.serverInfo[serverName]; found && previous.SameAs(info) {
+		return
+	}
+
+	if err := config.WriteServerInfo(serverName, info); err != nil {
+		if cp.Verbose {
+			logf("  > Can't save protocol version of %s: %s", serverName, err)
+		}
+		return
+	}
+	if cp.serverInfo == nil {
+		cp.serverInfo = map[string]config.ServerInfo{}
+	}
+	cp.serverInfo[serverName] = info
+}
+
+// cassettePath is where the sessions with a given server are recorded. Each
+// connection is tagged in the cassette so that they can be told apart.
+func cassettePath(dir string, serverName string) string {
+	return filepath.Join(dir, serverName+".jsonl")
+}
+
 func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error) {
 	cg.once.Do(func() {
 		createClient := func() (mcpclient.Client, error) {
@@ -421,49 +720,74 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 
 			var client mcpclient.Client
 
-			// Deprecated: Use Remote instead
-			if cg.serverConfig.Spec.SSEEndpoint != "" {
+			if cg.cp.ReplayDir != "" {
+				// Play back a recorded session instead of running the server.
+				client = mcpclient.NewReplayClient(cg.serverConfig.Name, cassettePath(cg.cp.ReplayDir, cg.serverConfig.Name))
+			} else if cg.serverConfig.Spec.SSEEndpoint != "" {
+				// Deprecated: Use Remote instead
 				client = mcpclient.NewRemoteMCPClient(cg.serverConfig)
 			} else if cg.serverConfig.Spec.Remote.URL != "" {
 				client = mcpclient.NewRemoteMCPClient(cg.serverConfig)
 			} else if cg.cp.Static {
 				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "socat", nil, "STDIO", fmt.Sprintf("TCP:mcp-%s:4444", cg.serverConfig.Name))
+			} else if cg.serverConfig.Spec.Package != nil && cg.cp.PackagesOnHost {
+				command, args, env, err := cg.cp.hostCommand(cg.serverConfig)
+				if err != nil {
+					return nil, err
+				}
+
+				log("  - Running", command, "on the host with arguments", args)
+				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, command, env, args...)
 			} else {
 				var targetConfig proxies.TargetConfig
 				if cg.cp.BlockNetwork && len(cg.serverConfig.Spec.AllowHosts) > 0 {
 					var err error
-					if targetConfig, cleanup, err = cg.cp.runProxies(ctx, cg.serverConfig.Spec.AllowHosts, cg.serverConfig.Spec.LongLived); err != nil {
+					if targetConfig, cleanup, err = cg.cp.runProxies(ctx, packageAllowHosts(cg.serverConfig.Spec), cg.serverConfig.Spec.LongLived); err != nil {
 						return nil, err
 					}
 				}
 
-				image := cg.serverConfig.Spec.Image
 				var readOnly *bool
 				if cg.clientConfig != nil {
 					readOnly = cg.clientConfig.readOnly
 				}
-				args, env := cg.cp.argsAndEnv(cg.serverConfig, readOnly, targetConfig)
+				spec, err := cg.cp.containerSpec(ctx, cg.serverConfig, readOnly, targetConfig)
+				if err != nil {
+					return nil, err
+				}
 
-				command := expandEnvList(eval.EvaluateList(cg.serverConfig.Spec.Command, cg.serverConfig.Config), env)
-				if len(command) == 0 {
-					log("  - Running", imageBaseName(image), "with", args)
+				if len(spec.Config.Cmd) == 0 {
+					log("  - Running", imageBaseName(spec.Config.Image))
 				} else {
-					log("  - Running", imageBaseName(image), "with", args, "and command", command)
+					log("  - Running", imageBaseName(spec.Config.Image), "with command", spec.Config.Cmd)
 				}
 
-				var runArgs []string
-				runArgs = append(runArgs, args...)
-				runArgs = append(runArgs, image)
-				runArgs = append(runArgs, command...)
+				if cg.serverConfig.Spec.Workspace {
+					roots := cg.cp.sessionRoots(cg.clientConfig)
+					cg.workspaceRoots = roots
+					cg.workspace = cg.cp.workspaceMounts(cg.serverConfig.Name, roots, readOnly != nil && *readOnly)
+					for _, mount := range cg.workspace {
+						spec.HostConfig.Binds = append(spec.HostConfig.Binds, mount.Volume)
+					}
+				}
+
+				client = mcpclient.NewStdioContainerClient(cg.serverConfig.Name, cg.cp.docker, spec)
+				if cg.serverConfig.Spec.Workspace {
+					client.AddRoots(containerRoots(cg.workspace, cg.cp.sessionRoots(cg.clientConfig)))
+				}
+			}
 
-				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "docker", env, runArgs...)
+			if cg.cp.RecordDir != "" {
+				client = mcpclient.WithRecording(client, cassettePath(cg.cp.RecordDir, cg.serverConfig.Name))
 			}
 
+			// The protocol version is negotiated by the SDK: it proposes the latest
+			// version it knows and accepts any older version it still supports.
 			initParams := &mcp.InitializeParams{
-				ProtocolVersion: "2024-11-05",
 				ClientInfo: &mcp.Implementation{
-					Name:    "docker",
-					Version: "1.0.0",
+					Name:    "docker-mcp-gateway",
+					Title:   "Docker MCP Gateway",
+					Version: gatewayVersion,
 				},
 			}
 
@@ -480,6 +804,7 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 			if err := client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway); err != nil {
 				return nil, err
 			}
+			cg.cp.recordServerInfo(ctx, cg.serverConfig.Name, client.Session().InitializeResult())
 
 			return newClientWithCleanup(client, cleanup), nil
 		}
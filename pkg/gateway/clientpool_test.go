@@ -3,11 +3,15 @@ package gateway
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/image"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -15,6 +19,8 @@ import (
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+	"github.com/docker/mcp-gateway/pkg/hardening"
+	"github.com/docker/mcp-gateway/pkg/mounts"
 )
 
 func TestApplyConfigGrafana(t *testing.T) {
@@ -36,14 +42,11 @@ grafana:
 		"grafana.api_key": "API_KEY",
 	}
 
-	args, env := argsAndEnv(t, "grafana", catalogYAML, configYAML, secrets, nil)
+	spec := containerSpec(t, "grafana", catalogYAML, configYAML, secrets, nil)
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=grafana", "-l", "docker-mcp-transport=stdio",
-		"-e", "GRAFANA_API_KEY", "-e", "GRAFANA_URL",
-	}, args)
-	assert.Equal(t, []string{"GRAFANA_API_KEY=API_KEY", "GRAFANA_URL=TEST"}, env)
+	assertBaseSpec(t, "grafana", spec)
+	assert.Equal(t, []string{"--transport=stdio"}, []string(spec.Config.Cmd))
+	assert.Equal(t, []string{"GRAFANA_API_KEY=API_KEY", "GRAFANA_URL=TEST"}, spec.Config.Env)
 }
 
 func TestApplyConfigMongoDB(t *testing.T) {
@@ -56,14 +59,12 @@ secrets:
 		"mongodb.connection_string": "HOST:PORT",
 	}
 
-	args, env := argsAndEnv(t, "mongodb", catalogYAML, "", secrets, nil)
+	spec := containerSpec(t, "mongodb", catalogYAML, "", secrets, nil)
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=mongodb", "-l", "docker-mcp-transport=stdio",
-		"-e", "MDB_MCP_CONNECTION_STRING",
-	}, args)
-	assert.Equal(t, []string{"MDB_MCP_CONNECTION_STRING=HOST:PORT"}, env)
+	assertBaseSpec(t, "mongodb", spec)
+	assert.Empty(t, spec.Config.Cmd)
+	assert.Empty(t, spec.HostConfig.Binds)
+	assert.Equal(t, []string{"MDB_MCP_CONNECTION_STRING=HOST:PORT"}, spec.Config.Env)
 }
 
 func TestApplyConfigNotion(t *testing.T) {
@@ -80,14 +81,12 @@ env:
 		"notion.internal_integration_token": "ntn_DUMMY",
 	}
 
-	args, env := argsAndEnv(t, "notion", catalogYAML, "", secrets, nil)
+	spec := containerSpec(t, "notion", catalogYAML, "", secrets, nil)
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=notion", "-l", "docker-mcp-transport=stdio",
-		"-e", "INTERNAL_INTEGRATION_TOKEN", "-e", "OPENAPI_MCP_HEADERS",
-	}, args)
-	assert.Equal(t, []string{"INTERNAL_INTEGRATION_TOKEN=ntn_DUMMY", `OPENAPI_MCP_HEADERS={"Authorization": "Bearer ntn_DUMMY", "Notion-Version": "2022-06-28"}`}, env)
+	assertBaseSpec(t, "notion", spec)
+	assert.Empty(t, spec.Config.Cmd)
+	assert.Empty(t, spec.HostConfig.Binds)
+	assert.Equal(t, []string{"INTERNAL_INTEGRATION_TOKEN=ntn_DUMMY", `OPENAPI_MCP_HEADERS={"Authorization": "Bearer ntn_DUMMY", "Notion-Version": "2022-06-28"}`}, spec.Config.Env)
 }
 
 func TestApplyConfigMountAs(t *testing.T) {
@@ -100,14 +99,11 @@ hub:
   log_path: /local/logs
 `
 
-	args, env := argsAndEnv(t, "hub", catalogYAML, configYAML, nil, nil)
+	spec := containerSpec(t, "hub", catalogYAML, configYAML, nil, nil)
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=hub", "-l", "docker-mcp-transport=stdio",
-		"-v", "/local/logs:/logs:ro",
-	}, args)
-	assert.Empty(t, env)
+	assertBaseSpec(t, "hub", spec)
+	assert.Equal(t, []string{"/local/logs:/logs:ro"}, spec.HostConfig.Binds)
+	assert.Empty(t, spec.Config.Env)
 }
 
 func TestApplyConfigEmptyMountAs(t *testing.T) {
@@ -116,13 +112,11 @@ volumes:
   - '{{hub.log_path|mount_as:/logs:ro}}'
   `
 
-	args, env := argsAndEnv(t, "hub", catalogYAML, "", nil, nil)
+	spec := containerSpec(t, "hub", catalogYAML, "", nil, nil)
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=hub", "-l", "docker-mcp-transport=stdio",
-	}, args)
-	assert.Empty(t, env)
+	assertBaseSpec(t, "hub", spec)
+	assert.Empty(t, spec.HostConfig.Binds)
+	assert.Empty(t, spec.Config.Env)
 }
 
 func TestApplyConfigMountAsReadOnly(t *testing.T) {
@@ -135,14 +129,10 @@ hub:
   log_path: /local/logs
 `
 
-	args, env := argsAndEnv(t, "hub", catalogYAML, configYAML, nil, readOnly())
+	spec := containerSpec(t, "hub", catalogYAML, configYAML, nil, readOnly())
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=hub", "-l", "docker-mcp-transport=stdio",
-		"-v", "/local/logs:/logs:ro",
-	}, args)
-	assert.Empty(t, env)
+	assert.Equal(t, []string{"/local/logs:/logs:ro"}, spec.HostConfig.Binds)
+	assert.Empty(t, spec.Config.Env)
 }
 
 func TestApplyConfigUser(t *testing.T) {
@@ -150,17 +140,70 @@ func TestApplyConfigUser(t *testing.T) {
 user: "1001:2002"
   `
 
-	args, env := argsAndEnv(t, "svc", catalogYAML, "", nil, nil)
+	spec := containerSpec(t, "svc", catalogYAML, "", nil, nil)
 
-	assert.Equal(t, []string{
-		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
-		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=svc", "-l", "docker-mcp-transport=stdio",
-		"-u", "1001:2002",
-	}, args)
-	assert.Empty(t, env)
+	assert.Equal(t, "1001:2002", spec.Config.User)
+	assert.Empty(t, spec.Config.Env)
 }
 
-func argsAndEnv(t *testing.T, name, catalogYAML, configYAML string, secrets map[string]string, readOnly *bool) ([]string, []string) {
+func TestApplyConfigHardenedProfile(t *testing.T) {
+	spec := containerSpec(t, "svc", "", "", nil, nil)
+
+	assert.True(t, spec.HostConfig.ReadonlyRootfs)
+	assert.Contains(t, spec.HostConfig.Tmpfs, "/tmp")
+	assert.Equal(t, []string{"ALL"}, []string(spec.HostConfig.CapDrop))
+	require.NotNil(t, spec.HostConfig.PidsLimit)
+	assert.Equal(t, int64(hardening.PidsLimit), *spec.HostConfig.PidsLimit)
+	assert.Empty(t, spec.Config.User)
+	assert.Equal(t, []string{"no-new-privileges"}, spec.HostConfig.SecurityOpt)
+}
+
+func TestApplyConfigRunAsNonRoot(t *testing.T) {
+	spec := containerSpec(t, "svc", "security: {runAsNonRoot: true}", "", nil, nil)
+
+	assert.Equal(t, hardening.NonRootUser, spec.Config.User)
+}
+
+func TestApplyConfigSecurityOptOut(t *testing.T) {
+	catalogYAML := `
+security:
+  writableRootfs: true
+  capAdd: [NET_BIND_SERVICE]
+  noPidsLimit: true
+  `
+
+	spec := containerSpec(t, "svc", catalogYAML, "", nil, nil)
+
+	assert.False(t, spec.HostConfig.ReadonlyRootfs)
+	assert.Empty(t, spec.HostConfig.Tmpfs)
+	assert.Equal(t, []string{"ALL"}, []string(spec.HostConfig.CapDrop))
+	assert.Equal(t, []string{"NET_BIND_SERVICE"}, []string(spec.HostConfig.CapAdd))
+	assert.Nil(t, spec.HostConfig.PidsLimit)
+	assert.Empty(t, spec.Config.User)
+}
+
+// assertBaseSpec checks what every server's container gets: its labels, an
+// init process, auto-removal, the default limits and no-new-privileges.
+func assertBaseSpec(t *testing.T, name string, spec docker.ContainerSpec) {
+	t.Helper()
+
+	assert.Equal(t, map[string]string{
+		"docker-mcp":           "true",
+		"docker-mcp-tool-type": "mcp",
+		"docker-mcp-name":      name,
+		"docker-mcp-transport": "stdio",
+	}, spec.Config.Labels)
+	assert.True(t, spec.Config.OpenStdin)
+	assert.True(t, spec.HostConfig.AutoRemove)
+	assert.Equal(t, boolPtr(true), spec.HostConfig.Init)
+	assert.Equal(t, []string{"no-new-privileges"}, spec.HostConfig.SecurityOpt)
+	assert.Equal(t, int64(1e9), spec.HostConfig.NanoCPUs)
+	assert.Equal(t, int64(2*1024*1024*1024), spec.HostConfig.Memory)
+	require.NotNil(t, spec.HostConfig.PidsLimit)
+	assert.Equal(t, int64(hardening.PidsLimit), *spec.HostConfig.PidsLimit)
+}
+
+func containerSpec(t *testing.T, name, catalogYAML, configYAML string, secrets map[string]string, readOnly *bool) docker.ContainerSpec {
 	t.Helper()
 
 	clientPool := &clientPool{
@@ -168,13 +211,27 @@ func argsAndEnv(t *testing.T, name, catalogYAML, configYAML string, secrets map[
 			Cpus:   1,
 			Memory: "2Gb",
 		},
+		docker: &fakeImages{},
 	}
-	return clientPool.argsAndEnv(&catalog.ServerConfig{
+	spec, err := clientPool.containerSpec(t.Context(), &catalog.ServerConfig{
 		Name:    name,
 		Spec:    parseSpec(t, catalogYAML),
 		Config:  parseConfig(t, configYAML),
 		Secrets: secrets,
 	}, readOnly, proxies.TargetConfig{})
+	require.NoError(t, err)
+	return spec
+}
+
+type fakeImages struct {
+	docker.Client
+	user string
+}
+
+func (f *fakeImages) InspectImage(context.Context, string) (image.InspectResponse, error) {
+	return image.InspectResponse{Config: &dockerspec.DockerOCIImageConfig{
+		ImageConfig: ocispec.ImageConfig{User: f.user},
+	}}, nil
 }
 
 func parseSpec(t *testing.T, contentYAML string) catalog.Server {
@@ -255,3 +312,40 @@ func TestStdioClientInitialization(t *testing.T) {
 
 	t.Logf("Successfully initialized stdio client and retrieved %d tools", len(tools.Tools))
 }
+
+func TestApplyConfigMountPolicy(t *testing.T) {
+	mountPolicy, err := mounts.Parse([]byte(`deny: [/local/secrets]`))
+	require.NoError(t, err)
+	clientPool := &clientPool{docker: &fakeImages{}, mountPolicy: mountPolicy}
+
+	_, err = clientPool.containerSpec(t.Context(), &catalog.ServerConfig{
+		Name: "hub",
+		Spec: parseSpec(t, `volumes: ["/local/secrets/key:/key"]`),
+	}, nil, proxies.TargetConfig{})
+	require.ErrorContains(t, err, "server hub: mounting /local/secrets/key:/key is not allowed")
+}
+
+func TestRecordServerInfoOnlyWritesChanges(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".docker", "mcp", "servers.json")
+
+	cp := &clientPool{}
+	initialized := &mcp.InitializeResult{
+		ProtocolVersion: "2025-06-18",
+		ServerInfo:      &mcp.Implementation{Name: "github", Version: "1.0.0"},
+	}
+
+	cp.recordServerInfo(t.Context(), "github", initialized)
+	require.FileExists(t, path)
+
+	require.NoError(t, os.Remove(path))
+	cp.recordServerInfo(t.Context(), "github", initialized)
+	assert.NoFileExists(t, path)
+
+	cp.recordServerInfo(t.Context(), "github", &mcp.InitializeResult{
+		ProtocolVersion: "2025-06-18",
+		ServerInfo:      &mcp.Implementation{Name: "github", Version: "1.1.0"},
+	})
+	require.FileExists(t, path)
+}
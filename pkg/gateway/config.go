@@ -1,6 +1,11 @@
 package gateway
 
-import "github.com/docker/mcp-gateway/pkg/catalog"
+import (
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
 
 type Config struct {
 	Options
@@ -9,30 +14,62 @@ type Config struct {
 	ConfigPath         []string
 	RegistryPath       []string
 	ToolsPath          []string
+	PromptsPath        []string
+	ResourcesPath      []string
 	SecretsPath        string
 	MCPRegistryServers []catalog.Server // catalog.Server objects from MCP registries
 }
 
 type Options struct {
-	Port                    int
-	Transport               string
-	ToolNames               []string
-	Interceptors            []string
-	OciRef                  []string
-	Verbose                 bool
-	LongLived               bool
-	DebugDNS                bool
-	LogCalls                bool
-	BlockSecrets            bool
-	BlockNetwork            bool
-	VerifySignatures        bool
-	DryRun                  bool
-	Watch                   bool
-	Cpus                    int
-	Memory                  string
-	Static                  bool
-	Central                 bool
-	OAuthInterceptorEnabled bool
-	McpOAuthDcrEnabled      bool
-	DynamicTools            bool
+	Port                        int
+	Transport                   string
+	ToolNames                   []string
+	PromptNames                 []string
+	ResourceNames               []string
+	Interceptors                []string
+	OciRef                      []string
+	MCPRegistryPrefer           oci.Source
+	Verbose                     bool
+	LongLived                   bool
+	DebugDNS                    bool
+	LogCalls                    bool
+	BlockSecrets                bool
+	BlockNetwork                bool
+	VerifySignatures            bool
+	DryRun                      bool
+	Watch                       bool
+	Cpus                        int
+	Memory                      string
+	SeccompProfile              string
+	PackagesOnHost              bool
+	Static                      bool
+	Central                     bool
+	OAuthInterceptorEnabled     bool
+	McpOAuthDcrEnabled          bool
+	DynamicTools                bool
+	AuditLog                    string
+	AuditLogMaxSize             int // In megabytes
+	AuditLogMaxBackups          int
+	AuditArguments              string
+	RecordDir                   string
+	ReplayDir                   string
+	ConfirmDestructive          bool
+	ConfirmTools                []string
+	PolicyPath                  string
+	MountPolicyPath             string
+	ProfilesPath                string
+	ToolOverridesPath           string
+	ValidateArguments           bool
+	CoerceArguments             bool
+	ValidateOutput              string
+	SynthesizeStructuredContent bool
+	OffloadThreshold            int // In bytes
+	OffloadDir                  string
+	OffloadTTL                  time.Duration
+	OffloadPageSize             int // In bytes
+	OffloadMaxSize              int // In MB
+	VirtualToolsPath            string
+	ToolsBudget                 int // In estimated tokens
+	ToolsBudgetStrategies       []string
+	ToolsBudgetWindow           time.Duration
 }
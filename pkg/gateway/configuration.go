@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -29,6 +30,8 @@ type Configuration struct {
 	servers     map[string]catalog.Server
 	config      map[string]map[string]any
 	tools       config.ToolsConfig
+	prompts     config.FilterConfig
+	resources   config.FilterConfig
 	secrets     map[string]string
 }
 
@@ -47,6 +50,10 @@ func (c *Configuration) DockerImages() []string {
 			log("MCP server not found:", serverName)
 		case serverConfig != nil && serverConfig.Spec.Image != "":
 			uniqueDockerImages[serverConfig.Spec.Image] = true
+		case serverConfig != nil && serverConfig.Spec.Package != nil:
+			if runtime, ok := packageRuntimes[serverConfig.Spec.Package.Runner]; ok {
+				uniqueDockerImages[runtime.Image] = true
+			}
 		case tools != nil:
 			for _, tool := range *tools {
 				uniqueDockerImages[tool.Container.Image] = true
@@ -72,7 +79,7 @@ func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[str
 	}
 
 	// Is it an MCP Server?
-	if server.Image != "" || server.SSEEndpoint != "" || server.Remote.URL != "" {
+	if server.Image != "" || server.Package != nil || server.SSEEndpoint != "" || server.Remote.URL != "" {
 		return &catalog.ServerConfig{
 			Name: serverName,
 			Spec: server,
@@ -97,8 +104,11 @@ type FileBasedConfiguration struct {
 	RegistryPath       []string
 	ConfigPath         []string
 	ToolsPath          []string
+	PromptsPath        []string
+	ResourcesPath      []string
 	SecretsPath        string           // Optional, if not set, use Docker Desktop's secrets API
 	OciRef             []string         // OCI references to fetch server definitions from
+	MCPRegistryPrefer  oci.Source       // What servers with both a package and a remote run
 	MCPRegistryServers []catalog.Server // Servers fetched from MCP registries
 	Watch              bool
 	Central            bool
@@ -156,6 +166,17 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 		}
 	}
 
+	var filterPaths []string
+	for _, path := range append(slices.Clone(c.PromptsPath), c.ResourcesPath...) {
+		if path != "" {
+			filterPath, err := config.FilePath(path)
+			if err != nil {
+				return Configuration{}, nil, nil, err
+			}
+			filterPaths = append(filterPaths, filterPath)
+		}
+	}
+
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return Configuration{}, nil, nil, err
@@ -215,6 +236,13 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 		}
 	}
 
+	// Add all prompts and resources paths to watcher
+	for _, path := range filterPaths {
+		if err := watcher.Add(path); err != nil && !os.IsNotExist(err) {
+			return Configuration{}, nil, nil, err
+		}
+	}
+
 	// Add token event file to watcher only if DCR feature is enabled
 	if c.isDCRFeatureEnabled() {
 		tokenEventPath := filepath.Join(os.Getenv("HOME"), ".docker", "mcp", TokenEventFilename)
@@ -307,6 +335,10 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 					imageName = imageName[:colonIdx] // Remove tag
 				}
 				serverName = fmt.Sprintf("mcp-registry-%s", imageName)
+			} else if mcpServer.Package != nil {
+				// Or the package name, without its npm scope
+				parts := strings.Split(mcpServer.Package.Name, "/")
+				serverName = fmt.Sprintf("mcp-registry-%s", parts[len(parts)-1])
 			}
 
 			// Ensure unique server name
@@ -347,6 +379,16 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		return Configuration{}, fmt.Errorf("reading tools: %w", err)
 	}
 
+	promptsConfig, err := c.readFilterConfig(ctx, "prompts", c.PromptsPath)
+	if err != nil {
+		return Configuration{}, fmt.Errorf("reading prompts: %w", err)
+	}
+
+	resourcesConfig, err := c.readFilterConfig(ctx, "resources", c.ResourcesPath)
+	if err != nil {
+		return Configuration{}, fmt.Errorf("reading resources: %w", err)
+	}
+
 	// TODO(dga): How do we know which secrets to read, in Central mode?
 	var secrets map[string]string
 	if c.SecretsPath == "docker-desktop" {
@@ -378,6 +420,8 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		servers:     servers,
 		config:      serversConfig,
 		tools:       serverToolsConfig,
+		prompts:     promptsConfig,
+		resources:   resourcesConfig,
 		secrets:     secrets,
 	}, nil
 }
@@ -496,6 +540,40 @@ func (c *FileBasedConfiguration) readToolsConfig(ctx context.Context) (config.To
 	return mergedToolsConfig, nil
 }
 
+// readFilterConfig reads the files that enable or disable prompts or resources.
+func (c *FileBasedConfiguration) readFilterConfig(ctx context.Context, kind string, paths []string) (config.FilterConfig, error) {
+	mergedFilterConfig := config.FilterConfig{
+		Servers: make(map[string][]string),
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		log("  - Reading "+kind+" from", path)
+		yaml, err := config.ReadConfigFile(ctx, c.docker, path)
+		if err != nil {
+			return config.FilterConfig{}, fmt.Errorf("reading %s file %s: %w", kind, path, err)
+		}
+
+		filterConfig, err := config.ParseFilterConfig(yaml)
+		if err != nil {
+			return config.FilterConfig{}, fmt.Errorf("parsing %s file %s: %w", kind, path, err)
+		}
+
+		// Merge servers into the combined filters, checking for overlaps
+		for serverName, filter := range filterConfig.Servers {
+			if _, exists := mergedFilterConfig.Servers[serverName]; exists {
+				log(fmt.Sprintf("Warning: overlapping server %s '%s' found in %s file '%s', overwriting previous value", kind, serverName, kind, path))
+			}
+			mergedFilterConfig.Servers[serverName] = filter
+		}
+	}
+
+	return mergedFilterConfig, nil
+}
+
 func (c *FileBasedConfiguration) readDockerDesktopSecrets(ctx context.Context, servers map[string]catalog.Server, serverNames []string) (map[string]string, error) {
 	// Use a map to deduplicate secret names
 	uniqueSecretNames := make(map[string]struct{})
@@ -663,8 +741,8 @@ func (c *FileBasedConfiguration) readServersFromOci(_ context.Context) (map[stri
 			// The ServerDetail is now directly available in ociServer.Server
 			serverDetail := ociServer.Server
 
-			// Transform ServerDetail to catalog.Server using the ToCatalogServer method
-			server := serverDetail.ToCatalogServer()
+			// Transform ServerDetail to catalog.Server, from the preferred source
+			server := serverDetail.ToCatalogServerFrom(c.MCPRegistryPrefer)
 
 			// Use the name from the ServerDetail if available, otherwise generate one
 			serverName := serverDetail.Name
@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const maxSummarizedValueLength = 120
+
+// confirmToolHandler asks the user, through elicitation, to approve calls to
+// destructive or open world tools, or to tools matching the --confirm-tools
+// patterns. Tools are known by the name clients see, which can differ from the
+// upstream name when the tool is overridden.
+func (g *Gateway) confirmToolHandler(serverName, toolName string, annotations *mcp.ToolAnnotations, handler mcp.ToolHandler) mcp.ToolHandler {
+	if !g.needsConfirmation(toolName, annotations) {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if g.isAlwaysAllowed(req.Session, serverName, toolName) {
+			return handler(ctx, req)
+		}
+
+		if !supportsElicitation(req.Session) {
+			return declinedResult(fmt.Sprintf("Calling %s requires a confirmation from the user but the client doesn't support elicitation.", toolName)), nil
+		}
+
+		result, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+			Message: confirmationMessage(serverName, toolName, req.Params.Arguments),
+			RequestedSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"alwaysAllow": {
+						Type:        "boolean",
+						Title:       "Always allow for this session",
+						Description: fmt.Sprintf("Don't ask again for %s until the end of the session", toolName),
+					},
+				},
+			},
+		})
+		if err != nil {
+			return declinedResult(fmt.Sprintf("Calling %s requires a confirmation from the user but it couldn't be obtained: %s", toolName, err)), nil
+		}
+		if result.Action != "accept" {
+			log("  - The user declined the call to", toolName)
+			return declinedResult(fmt.Sprintf("The user declined the call to %s.", toolName)), nil
+		}
+
+		if alwaysAllow, _ := result.Content["alwaysAllow"].(bool); alwaysAllow {
+			g.alwaysAllow(req.Session, serverName, toolName)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (g *Gateway) needsConfirmation(toolName string, annotations *mcp.ToolAnnotations) bool {
+	// Missing annotations get the defaults of the MCP spec: a tool is not
+	// read-only, and it can be destructive.
+	if annotations == nil {
+		annotations = &mcp.ToolAnnotations{}
+	}
+	destructive := !annotations.ReadOnlyHint && (annotations.DestructiveHint == nil || *annotations.DestructiveHint)
+
+	if g.ConfirmDestructive && destructive {
+		return true
+	}
+
+	for _, pattern := range g.ConfirmTools {
+		if matched, err := filepath.Match(pattern, toolName); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *Gateway) isAlwaysAllowed(ss *mcp.ServerSession, serverName, toolName string) bool {
+	g.sessionCacheMu.RLock()
+	defer g.sessionCacheMu.RUnlock()
+
+	cache, found := g.sessionCache[ss]
+	return found && cache.AllowedTools[serverName+"/"+toolName]
+}
+
+func (g *Gateway) alwaysAllow(ss *mcp.ServerSession, serverName, toolName string) {
+	g.sessionCacheMu.Lock()
+	defer g.sessionCacheMu.Unlock()
+
+	cache, found := g.sessionCache[ss]
+	if !found {
+		cache = &ServerSessionCache{}
+		g.sessionCache[ss] = cache
+	}
+	if cache.AllowedTools == nil {
+		cache.AllowedTools = map[string]bool{}
+	}
+	cache.AllowedTools[serverName+"/"+toolName] = true
+}
+
+func supportsElicitation(ss *mcp.ServerSession) bool {
+	if ss == nil {
+		return false
+	}
+	params := ss.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
+
+func declinedResult(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: message}},
+		IsError: true,
+	}
+}
+
+func confirmationMessage(serverName, toolName string, arguments any) string {
+	var message strings.Builder
+	fmt.Fprintf(&message, "Allow the call to %s (%s)?", toolName, serverName)
+
+	args, err := argumentsMap(arguments)
+	if err != nil || len(args) == 0 {
+		return message.String()
+	}
+
+	message.WriteString("\n\nArguments:")
+	for _, line := range summarizeArguments(args) {
+		message.WriteString("\n  " + line)
+	}
+	return message.String()
+}
+
+// summarizeArguments returns one line per argument, sorted by name, with long values truncated.
+func summarizeArguments(args map[string]any) []string {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		var value string
+		if s, ok := args[name].(string); ok {
+			value = s
+		} else if buf, err := json.Marshal(args[name]); err == nil {
+			value = string(buf)
+		}
+
+		if utf8.RuneCountInString(value) > maxSummarizedValueLength {
+			value = string([]rune(value)[:maxSummarizedValueLength]) + "..."
+		}
+		lines = append(lines, name+": "+value)
+	}
+	return lines
+}
@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsConfirmation(t *testing.T) {
+	destructive := &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)}
+	notDestructive := &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)}
+	readOnly := &mcp.ToolAnnotations{ReadOnlyHint: true}
+
+	g := &Gateway{Options: Options{ConfirmDestructive: true, ConfirmTools: []string{"delete_*"}}}
+	assert.True(t, g.needsConfirmation("drop_table", destructive))
+	assert.False(t, g.needsConfirmation("drop_table", notDestructive))
+	assert.False(t, g.needsConfirmation("drop_table", readOnly))
+	// Tools are destructive unless annotated otherwise.
+	assert.True(t, g.needsConfirmation("drop_table", nil))
+	assert.True(t, g.needsConfirmation("drop_table", &mcp.ToolAnnotations{}))
+	assert.True(t, g.needsConfirmation("delete_file", readOnly))
+
+	g = &Gateway{Options: Options{ConfirmTools: []string{"delete_*"}}}
+	assert.False(t, g.needsConfirmation("drop_table", destructive))
+}
+
+func TestSummarizeArguments(t *testing.T) {
+	lines := summarizeArguments(map[string]any{
+		"path":      "/tmp/file",
+		"recursive": true,
+		"content":   string(make([]byte, 200)),
+	})
+
+	require.Len(t, lines, 3)
+	assert.Len(t, lines[0], len("content: ")+maxSummarizedValueLength+len("..."))
+	assert.Equal(t, "path: /tmp/file", lines[1])
+	assert.Equal(t, "recursive: true", lines[2])
+
+	// Multi-byte characters are never cut in half.
+	lines = summarizeArguments(map[string]any{"text": strings.Repeat("é", 200)})
+	require.Len(t, lines, 1)
+	assert.True(t, utf8.ValidString(lines[0]))
+	assert.Equal(t, "text: "+strings.Repeat("é", maxSummarizedValueLength)+"...", lines[0])
+}
+
+func TestConfirmToolHandler(t *testing.T) {
+	ctx := t.Context()
+
+	g := &Gateway{
+		Options:      Options{ConfirmTools: []string{"delete_*"}},
+		sessionCache: map[*mcp.ServerSession]*ServerSessionCache{},
+	}
+
+	calls := 0
+	handler := g.confirmToolHandler("files", "delete_file", nil, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "deleted"}}}, nil
+	})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(&mcp.Tool{Name: "delete_file", InputSchema: &jsonschema.Schema{Type: "object"}}, handler)
+
+	elicitations := 0
+	answer := &mcp.ElicitResult{Action: "decline"}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			elicitations++
+			assert.Contains(t, req.Params.Message, "path: /tmp/file")
+			return answer, nil
+		},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	call := func() *mcp.CallToolResult {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "delete_file", Arguments: map[string]any{"path": "/tmp/file"}})
+		require.NoError(t, err)
+		return result
+	}
+
+	// Declined by the user
+	assert.True(t, call().IsError)
+	assert.Equal(t, 0, calls)
+
+	// Accepted once
+	answer = &mcp.ElicitResult{Action: "accept"}
+	assert.False(t, call().IsError)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 2, elicitations)
+
+	// Always allowed for the rest of the session
+	answer = &mcp.ElicitResult{Action: "accept", Content: map[string]any{"alwaysAllow": true}}
+	assert.False(t, call().IsError)
+	assert.False(t, call().IsError)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, elicitations)
+}
+
+func TestConfirmToolHandlerWithoutElicitation(t *testing.T) {
+	ctx := t.Context()
+
+	g := &Gateway{
+		Options:      Options{ConfirmTools: []string{"*"}},
+		sessionCache: map[*mcp.ServerSession]*ServerSessionCache{},
+	}
+	handler := g.confirmToolHandler("files", "delete_file", nil, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t.Fatal("the tool shouldn't be called")
+		return nil, nil
+	})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(&mcp.Tool{Name: "delete_file", InputSchema: &jsonschema.Schema{Type: "object"}}, handler)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "delete_file"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "doesn't support elicitation")
+}
@@ -380,6 +380,11 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 					Type:        "string",
 					Description: "URL to fetch the server details JSON (must be a valid HTTP/HTTPS URL)",
 				},
+				"prefer": {
+					Type:        "string",
+					Description: "What to run when the server is published both as a package and as a remote. Defaults to the gateway's choice",
+					Enum:        []any{string(oci.SourcePackage), string(oci.SourceRemote)},
+				},
 			},
 			Required: []string{"url"},
 		},
@@ -388,7 +393,8 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Parse parameters
 		var params struct {
-			URL string `json:"url"`
+			URL    string `json:"url"`
+			Prefer string `json:"prefer"`
 		}
 
 		if req.Params.Arguments == nil {
@@ -410,6 +416,13 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 
 		registryURL := strings.TrimSpace(params.URL)
 
+		source := g.MCPRegistryPrefer
+		if params.Prefer != "" {
+			if source, err = oci.ParseSource(params.Prefer); err != nil {
+				return nil, err
+			}
+		}
+
 		// Validate URL scheme
 		if !strings.HasPrefix(registryURL, "http://") && !strings.HasPrefix(registryURL, "https://") {
 			return &mcp.CallToolResult{
@@ -420,7 +433,7 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 		}
 
 		// Fetch servers from the URL
-		servers, err := g.readServersFromURL(ctx, registryURL)
+		servers, err := g.readServersFromURL(ctx, registryURL, source)
 		if err != nil {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{&mcp.TextContent{
@@ -504,7 +517,7 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 }
 
 // readServersFromURL fetches and parses server definitions from a URL
-func (g *Gateway) readServersFromURL(ctx context.Context, url string) (map[string]catalog.Server, error) {
+func (g *Gateway) readServersFromURL(ctx context.Context, url string, source oci.Source) (map[string]catalog.Server, error) {
 	servers := make(map[string]catalog.Server)
 
 	log(fmt.Sprintf("  - Reading servers from URL: %s", url))
@@ -540,7 +553,7 @@ func (g *Gateway) readServersFromURL(ctx context.Context, url string) (map[strin
 	var serverDetail oci.ServerDetail
 	if err := json.Unmarshal(body, &serverDetail); err == nil && serverDetail.Name != "" {
 		// Successfully parsed as ServerDetail - convert to catalog.Server
-		server := serverDetail.ToCatalogServer()
+		server := serverDetail.ToCatalogServerFrom(source)
 
 		serverName := serverDetail.Name
 		servers[serverName] = server
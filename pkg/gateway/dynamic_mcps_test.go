@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+func TestReadServersFromURLSource(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../../test/testdata/mcpregistry/server_package_and_remote.json")
+	}))
+	defer registry.Close()
+
+	g := &Gateway{}
+
+	servers, err := g.readServersFromURL(t.Context(), registry.URL, g.MCPRegistryPrefer)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	for _, server := range servers {
+		assert.Nil(t, server.Package)
+		assert.Equal(t, "https://mcp.tickets.example.com/mcp", server.Remote.URL)
+	}
+
+	servers, err = g.readServersFromURL(t.Context(), registry.URL, oci.SourcePackage)
+	require.NoError(t, err)
+	for _, server := range servers {
+		require.NotNil(t, server.Package)
+		assert.Equal(t, "@example/tickets-mcp", server.Package.Name)
+		assert.Empty(t, server.Remote.URL)
+	}
+}
@@ -34,13 +34,18 @@ func inferServerType(serverConfig *catalog.ServerConfig) string {
 		return "docker"
 	}
 
+	// Check for npm, PyPI or binary package
+	if serverConfig.Spec.Package != nil {
+		return "package"
+	}
+
 	// Unknown type
 	return "unknown"
 }
 
-func (g *Gateway) mcpToolHandler(tool catalog.Tool) mcp.ToolHandler {
+func (g *Gateway) mcpToolHandler(tool catalog.Tool, secrets map[string]string) mcp.ToolHandler {
 	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return g.clientPool.runToolContainer(ctx, tool, req.Params)
+		return g.clientPool.runToolContainer(ctx, tool, secrets, req.Params, newToolProgress(ctx, req))
 	}
 }
 
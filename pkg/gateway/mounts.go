@@ -0,0 +1,36 @@
+package gateway
+
+import (
+	"fmt"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/mounts"
+)
+
+func (g *Gateway) readMountPolicy() error {
+	if g.MountPolicyPath == "" {
+		return nil
+	}
+
+	path, err := config.FilePath(g.MountPolicyPath)
+	if err != nil {
+		return err
+	}
+
+	mountPolicy, err := mounts.Read(path)
+	if err != nil {
+		return err
+	}
+
+	log("- Mount policy enabled:", path, fmt.Sprintf("(%d allowed, %d denied, %d read-only paths)", len(mountPolicy.Allow), len(mountPolicy.Deny), len(mountPolicy.ReadOnly)))
+	g.clientPool.mountPolicy = mountPolicy
+	return nil
+}
+
+// checkMount rejects the volumes that the mount policy doesn't allow.
+func (cp *clientPool) checkMount(volume string) (string, error) {
+	if cp.mountPolicy == nil {
+		return volume, nil
+	}
+	return cp.mountPolicy.Check(volume)
+}
@@ -0,0 +1,211 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/results"
+)
+
+const (
+	resultsURIPrefix   = "gateway://results/"
+	resultsURITemplate = resultsURIPrefix + "{id}{?page}"
+	previewLength      = 500
+)
+
+func (g *Gateway) openResultsStore() error {
+	if g.OffloadThreshold <= 0 {
+		return nil
+	}
+
+	store, err := results.NewStore(g.OffloadDir, g.OffloadTTL, int64(g.OffloadMaxSize)*1024*1024)
+	if err != nil {
+		return err
+	}
+
+	log("- Offloading tool results larger than", g.OffloadThreshold, "bytes")
+	g.results = store
+	g.mcpServer.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "tool-results",
+		Title:       "Large tool results",
+		Description: "Tool results that were too large to be returned directly. Read them page by page.",
+		URITemplate: resultsURITemplate,
+	}, g.readResultHandler)
+
+	return nil
+}
+
+// offloadToolHandler replaces the large contents of a result with a short
+// preview and a link to a resource, from which the content can be read in pages.
+func (g *Gateway) offloadToolHandler(handler mcp.ToolHandler) mcp.ToolHandler {
+	if g.results == nil {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || req.Session == nil {
+			return result, err
+		}
+
+		offloaded := false
+		contents := make([]mcp.Content, 0, len(result.Content))
+		for _, content := range result.Content {
+			replacement, err := g.offloadContent(req.Session, req.Params.Name, content)
+			if err != nil {
+				logf("  - Can't offload the result of %s: %s", req.Params.Name, err)
+				contents = append(contents, content)
+				continue
+			}
+			if replacement == nil {
+				contents = append(contents, content)
+				continue
+			}
+
+			offloaded = true
+			contents = append(contents, replacement...)
+		}
+		if !offloaded {
+			return result, nil
+		}
+
+		trimmed := *result
+		trimmed.Content = contents
+		// The structured content would be as large as what was just offloaded.
+		if buf, err := json.Marshal(result.StructuredContent); err == nil && len(buf) > g.OffloadThreshold {
+			trimmed.StructuredContent = nil
+		}
+
+		return &trimmed, nil
+	}
+}
+
+// offloadContent stores a content that is above the threshold and returns what
+// should replace it, or nil if the content is small enough.
+func (g *Gateway) offloadContent(ss *mcp.ServerSession, toolName string, content mcp.Content) ([]mcp.Content, error) {
+	var (
+		result  results.Result
+		preview string
+	)
+	switch c := content.(type) {
+	case *mcp.TextContent:
+		if len(c.Text) <= g.OffloadThreshold {
+			return nil, nil
+		}
+		result = results.Result{MIMEType: "text/plain", Data: []byte(c.Text)}
+		if json.Valid(result.Data) {
+			result.MIMEType = "application/json"
+		}
+		preview = truncateText(c.Text, previewLength)
+	case *mcp.ImageContent:
+		if len(c.Data) <= g.OffloadThreshold {
+			return nil, nil
+		}
+		result = results.Result{MIMEType: c.MIMEType, Binary: true, Data: c.Data}
+	case *mcp.AudioContent:
+		if len(c.Data) <= g.OffloadThreshold {
+			return nil, nil
+		}
+		result = results.Result{MIMEType: c.MIMEType, Binary: true, Data: c.Data}
+	default:
+		return nil, nil
+	}
+
+	id, stored, err := g.results.Put(sessionID(ss), result)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := resultsURIPrefix + id
+	size := int64(len(result.Data))
+	logf("  - Offloaded %d bytes of the result of %s to %s", size, toolName, uri)
+
+	var summary strings.Builder
+	if result.Binary {
+		fmt.Fprintf(&summary, "The result of %s contains a %s of %d bytes, too large to be returned directly. It was stored as %s. Read it with resources/read before %s.", toolName, result.MIMEType, size, uri, stored.Expires.Format(time.RFC3339))
+	} else {
+		pages := results.Pages(result.Data, g.OffloadPageSize)
+		fmt.Fprintf(&summary, "The result of %s is %d bytes, too large to be returned directly. It was stored as %s, in %d page(s). Read the pages with resources/read, eg. %s?page=2, before %s.", toolName, size, uri, pages, uri, stored.Expires.Format(time.RFC3339))
+		summary.WriteString("\n\nPreview:\n" + preview)
+	}
+
+	return []mcp.Content{
+		&mcp.TextContent{Text: summary.String()},
+		&mcp.ResourceLink{
+			URI:      uri,
+			Name:     "result-" + id,
+			Title:    "Result of " + toolName,
+			MIMEType: result.MIMEType,
+			Size:     &size,
+		},
+	}, nil
+}
+
+func (g *Gateway) readResultHandler(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	id, page, err := parseResultURI(req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := g.results.Get(sessionID(req.Session), id)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+
+	if result.Binary {
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: result.MIMEType, Blob: result.Data}},
+		}, nil
+	}
+
+	pages := results.Pages(result.Data, g.OffloadPageSize)
+	content, ok := results.Page(result.Data, g.OffloadPageSize, page)
+	if !ok {
+		return nil, fmt.Errorf("invalid page %d: %s has %d page(s)", page, resultsURIPrefix+id, pages)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      req.Params.URI,
+			MIMEType: result.MIMEType,
+			Text:     string(content),
+			Meta:     mcp.Meta{"page": page, "pages": pages},
+		}},
+	}, nil
+}
+
+func parseResultURI(uri string) (string, int, error) {
+	u, err := url.Parse(uri)
+	if err != nil || !strings.HasPrefix(uri, resultsURIPrefix) {
+		return "", 0, fmt.Errorf("invalid result URI %q", uri)
+	}
+
+	id := strings.TrimPrefix(u.Path, "/")
+
+	page := 1
+	if value := u.Query().Get("page"); value != "" {
+		if page, err = strconv.Atoi(value); err != nil {
+			return "", 0, fmt.Errorf("invalid page %q", value)
+		}
+	}
+
+	return id, page, nil
+}
+
+func truncateText(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	for length > 0 && !utf8.RuneStart(text[length]) {
+		length--
+	}
+	return text[:length] + "..."
+}
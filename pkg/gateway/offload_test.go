@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffloadLargeResults(t *testing.T) {
+	ctx := t.Context()
+
+	g := &Gateway{
+		Options: Options{
+			OffloadThreshold: 100,
+			OffloadTTL:       time.Minute,
+			OffloadPageSize:  150,
+		},
+		mcpServer: mcp.NewServer(&mcp.Implementation{Name: "gateway"}, &mcp.ServerOptions{HasResources: true}),
+	}
+	require.NoError(t, g.openResultsStore())
+	defer g.results.Close()
+
+	large := strings.Repeat("0123456789", 20)
+	g.mcpServer.AddTool(&mcp.Tool{Name: "dump", InputSchema: &jsonschema.Schema{Type: "object"}}, g.offloadToolHandler(func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			&mcp.TextContent{Text: "small"},
+			&mcp.TextContent{Text: large},
+		}}, nil
+	}))
+
+	connect := func() *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := g.mcpServer.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { serverSession.Close() })
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { session.Close() })
+		return session
+	}
+
+	session := connect()
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "dump"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 3)
+	assert.Equal(t, "small", result.Content[0].(*mcp.TextContent).Text)
+	assert.Contains(t, result.Content[1].(*mcp.TextContent).Text, "in 2 page(s)")
+	link := result.Content[2].(*mcp.ResourceLink)
+	assert.True(t, strings.HasPrefix(link.URI, "gateway://results/"))
+	assert.Equal(t, int64(200), *link.Size)
+
+	page1, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: link.URI})
+	require.NoError(t, err)
+	page2, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: link.URI + "?page=2"})
+	require.NoError(t, err)
+	assert.Equal(t, large, page1.Contents[0].Text+page2.Contents[0].Text)
+
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: link.URI + "?page=3"})
+	require.ErrorContains(t, err, "invalid page 3")
+
+	// Other sessions can't read the result.
+	_, err = connect().ReadResource(ctx, &mcp.ReadResourceParams{URI: link.URI})
+	require.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	OutputValidationOff   = "off"
+	OutputValidationWarn  = "warn"
+	OutputValidationStrip = "strip"
+	OutputValidationFail  = "fail"
+)
+
+// outputToolHandler checks the structured results of tools that declare an
+// output schema. It can also synthesize the structured result of tools that
+// only return JSON as text.
+func (g *Gateway) outputToolHandler(serverName string, tool *mcp.Tool, handler mcp.ToolHandler) mcp.ToolHandler {
+	validate := g.ValidateOutput != "" && g.ValidateOutput != OutputValidationOff
+	if tool.OutputSchema == nil || (!validate && !g.SynthesizeStructuredContent) {
+		return handler
+	}
+
+	var resolved *jsonschema.Resolved
+	if validate {
+		// Many servers declare an older draft but the validator only knows 2020-12.
+		schema := *tool.OutputSchema
+		schema.Schema = ""
+
+		var err error
+		resolved, err = schema.Resolve(nil)
+		if err != nil {
+			logf("  > Can't validate the output of %s/%s: %s", serverName, tool.Name, err)
+		}
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		if result.StructuredContent == nil && g.SynthesizeStructuredContent {
+			result.StructuredContent = structuredContentFromText(result.Content)
+		}
+
+		if resolved == nil {
+			return result, nil
+		}
+
+		violation := validateStructuredContent(resolved, result.StructuredContent)
+		if violation == nil {
+			return result, nil
+		}
+
+		switch g.ValidateOutput {
+		case OutputValidationStrip:
+			logf("  - Stripping invalid structured output of %s/%s: %s", serverName, req.Params.Name, violation)
+			result.StructuredContent = nil
+		case OutputValidationFail:
+			logf("  - Invalid structured output of %s/%s: %s", serverName, req.Params.Name, violation)
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("The output of %s doesn't match its output schema: %s", req.Params.Name, violation)}},
+				IsError: true,
+			}, nil
+		default:
+			logf("  - Invalid structured output of %s/%s: %s", serverName, req.Params.Name, violation)
+		}
+
+		return result, nil
+	}
+}
+
+func validateStructuredContent(resolved *jsonschema.Resolved, structuredContent any) error {
+	if structuredContent == nil {
+		return errors.New("no structured content")
+	}
+
+	// Normalize to the JSON types the validator expects.
+	buf, err := json.Marshal(structuredContent)
+	if err != nil {
+		return err
+	}
+	var value any
+	if err := json.Unmarshal(buf, &value); err != nil {
+		return err
+	}
+
+	if err := resolved.Validate(value); err != nil {
+		return rootCause(err)
+	}
+	return nil
+}
+
+// structuredContentFromText returns the first text content that is a JSON object.
+func structuredContentFromText(contents []mcp.Content) any {
+	for _, content := range contents {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+
+		var value map[string]any
+		if err := json.Unmarshal([]byte(text.Text), &value); err == nil && value != nil {
+			return value
+		}
+	}
+
+	return nil
+}
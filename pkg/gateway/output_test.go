@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func weatherTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "weather",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+		OutputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"temperature": {Type: "number"},
+			},
+			Required: []string{"temperature"},
+		},
+	}
+}
+
+func callWeather(t *testing.T, g *Gateway, result *mcp.CallToolResult) *mcp.CallToolResult {
+	t.Helper()
+
+	handler := g.outputToolHandler("weather", weatherTool(), func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return result, nil
+	})
+
+	result, err := handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{Name: "weather"}})
+	require.NoError(t, err)
+	return result
+}
+
+func TestValidateOutput(t *testing.T) {
+	invalid := func() *mcp.CallToolResult {
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{&mcp.TextContent{Text: `{"temperature": "hot"}`}},
+			StructuredContent: map[string]any{"temperature": "hot"},
+		}
+	}
+	valid := &mcp.CallToolResult{StructuredContent: map[string]any{"temperature": 21.5}}
+
+	g := &Gateway{Options: Options{ValidateOutput: OutputValidationWarn}}
+	assert.Equal(t, invalid(), callWeather(t, g, invalid()))
+	assert.Equal(t, valid, callWeather(t, g, valid))
+
+	g = &Gateway{Options: Options{ValidateOutput: OutputValidationStrip}}
+	result := callWeather(t, g, invalid())
+	assert.False(t, result.IsError)
+	assert.Nil(t, result.StructuredContent)
+	assert.Len(t, result.Content, 1)
+	assert.Equal(t, valid, callWeather(t, g, valid))
+
+	g = &Gateway{Options: Options{ValidateOutput: OutputValidationFail}}
+	result = callWeather(t, g, invalid())
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "doesn't match its output schema")
+	assert.Equal(t, valid, callWeather(t, g, valid))
+
+	// Results without structured content don't match the schema either.
+	result = callWeather(t, g, &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "21.5"}}})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "no structured content")
+}
+
+func TestSynthesizeStructuredContent(t *testing.T) {
+	g := &Gateway{Options: Options{ValidateOutput: OutputValidationFail, SynthesizeStructuredContent: true}}
+
+	result := callWeather(t, g, &mcp.CallToolResult{Content: []mcp.Content{
+		&mcp.TextContent{Text: "The weather is:"},
+		&mcp.TextContent{Text: `{"temperature": 21.5}`},
+	}})
+	assert.False(t, result.IsError)
+	assert.Equal(t, map[string]any{"temperature": 21.5}, result.StructuredContent)
+
+	// Tools without an output schema are left alone.
+	handler := g.outputToolHandler("search", &mcp.Tool{Name: "search"}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: `{"results": []}`}}}, nil
+	})
+	result, err := handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{Name: "search"}})
+	require.NoError(t, err)
+	assert.Nil(t, result.StructuredContent)
+}
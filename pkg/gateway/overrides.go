@@ -0,0 +1,172 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/overrides"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+func (g *Gateway) readToolOverrides() error {
+	if g.ToolOverridesPath == "" {
+		return nil
+	}
+
+	path, err := config.FilePath(g.ToolOverridesPath)
+	if err != nil {
+		return err
+	}
+
+	toolOverrides, err := overrides.Read(path)
+	if err != nil {
+		return err
+	}
+
+	log("- Tool overrides enabled:", path)
+	g.overrides = toolOverrides
+	return nil
+}
+
+// overrideTool rewrites a tool as it should be exposed to clients. The handler
+// translates calls back to the upstream name and arguments, and transforms the
+// results. Argument values can be templated from the server's config.
+func (g *Gateway) overrideTool(serverName string, config map[string]any, tool *mcp.Tool, handler mcp.ToolHandler) (*mcp.Tool, mcp.ToolHandler) {
+	if g.overrides == nil {
+		return tool, handler
+	}
+	override := g.overrides.For(serverName, tool)
+	if override == nil {
+		return tool, handler
+	}
+
+	upstreamName := tool.Name
+	return override.Apply(tool, config), func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := argumentsMap(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", req.Params.Name, err)
+		}
+
+		translated, err := override.TranslateArguments(arguments, config)
+		if err != nil {
+			return nil, fmt.Errorf("can't call %s: %w", req.Params.Name, err)
+		}
+
+		params := *req.Params
+		params.Name = upstreamName
+		params.Arguments = translated
+
+		result, err := handler(ctx, &mcp.CallToolRequest{
+			Session: req.Session,
+			Params:  &params,
+			Extra:   req.Extra,
+		})
+		if err != nil || result == nil || result.IsError || override.Response == nil {
+			return result, err
+		}
+
+		return transformResult(ctx, serverName, upstreamName, override.Response, result), nil
+	}
+}
+
+// exposedToolName is the name of a tool, as seen by clients.
+func (g *Gateway) exposedToolName(serverName string, tool *mcp.Tool) string {
+	if g.overrides != nil {
+		if override := g.overrides.For(serverName, tool); override != nil && override.Name != "" {
+			return override.Name
+		}
+	}
+	return tool.Name
+}
+
+// rejectRenameCollisions drops the renamed tools whose new name is already
+// taken by another tool, from the same server or not. Otherwise, registering
+// the renamed tool would silently replace the other one.
+func rejectRenameCollisions(tools []ToolRegistration) []ToolRegistration {
+	owners := map[string][]ToolRegistration{}
+	for _, tool := range tools {
+		owners[tool.Tool.Name] = append(owners[tool.Tool.Name], tool)
+	}
+
+	var kept []ToolRegistration
+	for _, tool := range tools {
+		renamed := tool.UpstreamName != "" && tool.UpstreamName != tool.Tool.Name
+		if renamed && len(owners[tool.Tool.Name]) > 1 {
+			for _, other := range owners[tool.Tool.Name] {
+				if other.ServerName != tool.ServerName || other.UpstreamName != tool.UpstreamName {
+					logf("  > Can't rename %s/%s to %s: %s/%s already has that name", tool.ServerName, tool.UpstreamName, tool.Tool.Name, other.ServerName, other.UpstreamName)
+					break
+				}
+			}
+			continue
+		}
+		kept = append(kept, tool)
+	}
+
+	return kept
+}
+
+// transformResult applies a response transform to the JSON text contents and
+// to the structured content of a result. Contents that are not JSON are left
+// untouched.
+func transformResult(ctx context.Context, serverName, toolName string, transform *overrides.ResponseTransform, result *mcp.CallToolResult) *mcp.CallToolResult {
+	originalSize := resultSize(result)
+
+	transformed := *result
+	transformed.Content = make([]mcp.Content, len(result.Content))
+	for i, content := range result.Content {
+		transformed.Content[i] = content
+
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal([]byte(text.Text), &value); err != nil {
+			continue
+		}
+
+		value, err := transform.Apply(value)
+		if err != nil {
+			logf("  - Can't transform the result of %s/%s: %s", serverName, toolName, err)
+			continue
+		}
+		buf, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+
+		transformedText := *text
+		transformedText.Text = string(buf)
+		transformed.Content[i] = &transformedText
+	}
+
+	if result.StructuredContent != nil {
+		var value any
+		if buf, err := json.Marshal(result.StructuredContent); err == nil && json.Unmarshal(buf, &value) == nil {
+			if value, err := transform.Apply(value); err != nil {
+				logf("  - Can't transform the structured result of %s/%s: %s", serverName, toolName, err)
+			} else if _, isObject := value.(map[string]any); isObject {
+				transformed.StructuredContent = value
+			} else {
+				// Structured content must be an object.
+				transformed.StructuredContent = map[string]any{"result": value}
+			}
+		}
+	}
+
+	telemetry.RecordToolResultTransform(ctx, serverName, toolName, originalSize, resultSize(&transformed))
+	return &transformed
+}
+
+func resultSize(result *mcp.CallToolResult) int {
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return 0
+	}
+	return len(buf)
+}
@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/overrides"
+)
+
+func TestOverrideTool(t *testing.T) {
+	toolOverrides, err := overrides.Parse([]byte(`
+github-official:
+  search_repositories:
+    name: find_repos
+    arguments:
+      perPage:
+        value: 10
+`))
+	require.NoError(t, err)
+	g := &Gateway{overrides: toolOverrides}
+
+	var upstream *mcp.CallToolParams
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		upstream = req.Params
+		return &mcp.CallToolResult{}, nil
+	}
+
+	tool := &mcp.Tool{Name: "search_repositories", InputSchema: &jsonschema.Schema{Type: "object"}}
+	overridden, overriddenHandler := g.overrideTool("github-official", nil, tool, handler)
+	assert.Equal(t, "find_repos", overridden.Name)
+
+	_, err = overriddenHandler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{
+		Name:      "find_repos",
+		Arguments: map[string]any{"query": "mcp"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "search_repositories", upstream.Name)
+	assert.Equal(t, map[string]any{"query": "mcp", "perPage": 10}, upstream.Arguments)
+
+	// Tools without overrides are left as is.
+	other := &mcp.Tool{Name: "get_issue", InputSchema: &jsonschema.Schema{Type: "object"}}
+	notOverridden, _ := g.overrideTool("github-official", nil, other, handler)
+	assert.Same(t, other, notOverridden)
+
+	assert.Equal(t, "find_repos", g.exposedToolName("github-official", tool))
+	assert.Equal(t, "get_issue", g.exposedToolName("github-official", other))
+}
+
+func TestTransformResult(t *testing.T) {
+	toolOverrides, err := overrides.Parse([]byte(`
+github-official:
+  "*":
+    response:
+      jsonpath: "$.items"
+      include: [name]
+`))
+	require.NoError(t, err)
+	g := &Gateway{overrides: toolOverrides}
+
+	tool := &mcp.Tool{
+		Name:         "search_repositories",
+		InputSchema:  &jsonschema.Schema{Type: "object"},
+		OutputSchema: &jsonschema.Schema{Type: "object"},
+	}
+	upstream := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: "Found 2 repositories"},
+			&mcp.TextContent{Text: `{"items": [{"name": "a", "url": "https://a"}, {"name": "b", "url": "https://b"}]}`},
+		},
+		StructuredContent: map[string]any{"items": []any{map[string]any{"name": "a", "url": "https://a"}}},
+	}
+	overridden, handler := g.overrideTool("github-official", nil, tool, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return upstream, nil
+	})
+	assert.Nil(t, overridden.OutputSchema)
+
+	result, err := handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{Name: "search_repositories"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Found 2 repositories", result.Content[0].(*mcp.TextContent).Text)
+	assert.JSONEq(t, `[{"name": "a"}, {"name": "b"}]`, result.Content[1].(*mcp.TextContent).Text)
+	assert.Equal(t, map[string]any{"result": []any{map[string]any{"name": "a"}}}, result.StructuredContent)
+
+	// The upstream result is left untouched.
+	assert.Contains(t, upstream.Content[1].(*mcp.TextContent).Text, "https://a")
+}
+
+func TestRejectRenameCollisions(t *testing.T) {
+	registration := func(serverName, upstreamName, name string) ToolRegistration {
+		return ToolRegistration{ServerName: serverName, UpstreamName: upstreamName, Tool: &mcp.Tool{Name: name}}
+	}
+
+	tools := rejectRenameCollisions([]ToolRegistration{
+		registration("github-official", "search_repositories", "search"),
+		registration("github-official", "list_issues", "get_pull_request"),
+		registration("github-official", "get_pull_request", "get_pull_request"),
+		registration("github-official", "get_issue", "issue"),
+		registration("duckduckgo", "search", "search"),
+	})
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.ServerName+"/"+tool.UpstreamName)
+	}
+	// The tools that were renamed to the name of another tool, from the same
+	// server or not, are dropped.
+	assert.Equal(t, []string{"github-official/get_pull_request", "github-official/get_issue", "duckduckgo/search"}, names)
+}
@@ -0,0 +1,201 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/hardening"
+)
+
+const (
+	runnerNpx    = "npx"
+	runnerUvx    = "uvx"
+	runnerBinary = "binary"
+
+	// packageCacheDir is where the package cache is mounted in the runtime containers.
+	packageCacheDir = "/cache"
+
+	// binaryDir is where binaries are mounted in the runtime containers.
+	binaryDir = "/usr/local/bin"
+)
+
+// packageRuntime is the generic container that runs the servers published
+// with a given runner.
+type packageRuntime struct {
+	Image string
+	// CacheVolume keeps the downloaded packages across runs. Each server gets
+	// its own volume, suffixed with its name, so that a package can't tamper
+	// with the packages cached for other servers.
+	CacheVolume string
+	Env         []string
+	// Registries are the hosts the packages are downloaded from. They are
+	// added to the hosts a server can reach when the network is blocked.
+	Registries []string
+}
+
+var packageRuntimes = map[string]packageRuntime{
+	runnerNpx: {
+		Image:       "node:22-alpine",
+		CacheVolume: "docker-mcp-npm-cache",
+		Env:         []string{"npm_config_cache=" + packageCacheDir, "npm_config_update_notifier=false", "HOME=/tmp"},
+		Registries:  []string{"registry.npmjs.org:443"},
+	},
+	runnerUvx: {
+		Image:       "ghcr.io/astral-sh/uv:python3.12-alpine",
+		CacheVolume: "docker-mcp-uv-cache",
+		Env:         []string{"UV_CACHE_DIR=" + packageCacheDir, "HOME=/tmp"},
+		Registries:  []string{"pypi.org:443", "files.pythonhosted.org:443"},
+	},
+	runnerBinary: {
+		Image: "debian:bookworm-slim",
+	},
+}
+
+func runtimeOf(pkg *catalog.Package) (packageRuntime, error) {
+	runtime, ok := packageRuntimes[pkg.Runner]
+	if !ok {
+		return packageRuntime{}, fmt.Errorf("unknown package runner %q: must be npx, uvx or binary", pkg.Runner)
+	}
+	if pkg.Name == "" {
+		return packageRuntime{}, fmt.Errorf("missing name of the %s package", pkg.Runner)
+	}
+	// The binary is mounted from the host, where a relative path would
+	// depend on the gateway's working directory.
+	if pkg.Runner == runnerBinary && !filepath.IsAbs(pkg.Name) {
+		return packageRuntime{}, fmt.Errorf("the path of the binary package %q must be absolute", pkg.Name)
+	}
+	return runtime, nil
+}
+
+// packageCommand is the command that runs a package, given the path of the
+// binary for binary packages.
+func packageCommand(pkg *catalog.Package, binaryPath string) []string {
+	switch pkg.Runner {
+	case runnerNpx:
+		name := pkg.Name
+		if pkg.Version != "" {
+			name += "@" + pkg.Version
+		}
+		return slices.Concat([]string{"npx", "-y"}, pkg.RuntimeArgs, []string{name})
+	case runnerUvx:
+		name := pkg.Name
+		if pkg.Version != "" {
+			name += "==" + pkg.Version
+		}
+		return slices.Concat([]string{"uvx"}, pkg.RuntimeArgs, []string{name})
+	default:
+		return []string{binaryPath}
+	}
+}
+
+// withPackage turns the container of a server into the runtime container of
+// its package: the runtime image, the package cache and the runner's command,
+// followed by the server's arguments.
+func (cp *clientPool) withPackage(spec *docker.ContainerSpec, serverName string, pkg *catalog.Package) error {
+	runtime, err := runtimeOf(pkg)
+	if err != nil {
+		return err
+	}
+
+	binaryPath := ""
+	if pkg.Runner == runnerBinary {
+		binaryPath = path.Join(binaryDir, filepath.Base(pkg.Name))
+		mount, err := cp.checkMount(pkg.Name + ":" + binaryPath + ":ro")
+		if err != nil {
+			return err
+		}
+		spec.HostConfig.Binds = append(spec.HostConfig.Binds, mount)
+	}
+	if runtime.CacheVolume != "" {
+		spec.HostConfig.Binds = append(spec.HostConfig.Binds, cacheVolume(runtime, serverName)+":"+packageCacheDir)
+	}
+
+	// The server's env comes last so that it can override the runtime's.
+	spec.Config.Env = slices.Concat(runtime.Env, spec.Config.Env)
+	spec.Config.Image = runtime.Image
+	spec.Config.Cmd = slices.Concat(packageCommand(pkg, binaryPath), spec.Config.Cmd)
+
+	return nil
+}
+
+// cacheVolume is the name of the volume that caches the packages of a server.
+// Characters that can't be used in a volume name are replaced.
+func cacheVolume(runtime packageRuntime, serverName string) string {
+	suffix := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, serverName)
+	return runtime.CacheVolume + "-" + suffix
+}
+
+// packageSecurity is the security of a package's runtime container. Like the
+// cache volume it writes to, it runs as root even if the server opts in to a
+// non-root user, although still without any capability and on a read-only
+// root filesystem.
+func packageSecurity(pkg *catalog.Package, security catalog.Security) catalog.Security {
+	if runtime, ok := packageRuntimes[pkg.Runner]; ok && runtime.CacheVolume != "" {
+		security.RunAsNonRoot = false
+	}
+	return security
+}
+
+// SecurityProfile is the hardened profile the container of a server runs with.
+// Package servers run in the runtime container of their runner.
+func SecurityProfile(ctx context.Context, dockerClient docker.Client, server catalog.Server, seccompProfile string) hardening.Profile {
+	image, security := server.Image, server.Security
+	if server.Package != nil {
+		image = packageRuntimes[server.Package.Runner].Image
+		security = packageSecurity(server.Package, security)
+	}
+	return hardening.New(security, imageUser(ctx, dockerClient, image), seccompProfile)
+}
+
+// packageAllowHosts are the hosts a package server can reach when the network
+// is blocked: its own, plus the registry of its runner.
+func packageAllowHosts(server catalog.Server) []string {
+	if server.Package == nil || len(server.AllowHosts) == 0 {
+		return server.AllowHosts
+	}
+	return slices.Concat(server.AllowHosts, packageRuntimes[server.Package.Runner].Registries)
+}
+
+// hostEnv lists the variables of the gateway's env that packages running on
+// the host get. The runners need them to find their binaries, their cache and
+// a temporary directory, and Windows needs SystemRoot to run anything.
+var hostEnv = []string{"PATH", "HOME", "USERPROFILE", "TMPDIR", "TEMP", "TMP", "SystemRoot"}
+
+// hostCommand is the command and env that run a package directly on the host.
+// This is only allowed with --packages-on-host, and never for servers whose
+// network access is restricted, since that can't be enforced on the host.
+// Apart from a few variables, the package doesn't see the gateway's env.
+func (cp *clientPool) hostCommand(serverConfig *catalog.ServerConfig) (string, []string, []string, error) {
+	if serverConfig.Spec.DisableNetwork || (cp.BlockNetwork && len(serverConfig.Spec.AllowHosts) > 0) {
+		return "", nil, nil, fmt.Errorf("server %s restricts its network access and can't run on the host", serverConfig.Name)
+	}
+
+	pkg := serverConfig.Spec.Package
+	if _, err := runtimeOf(pkg); err != nil {
+		return "", nil, nil, fmt.Errorf("server %s: %w", serverConfig.Name, err)
+	}
+
+	env := serverEnv(serverConfig, nil)
+	command := slices.Concat(packageCommand(pkg, pkg.Name), serverArgs(serverConfig, env))
+
+	var baseEnv []string
+	for _, name := range hostEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			baseEnv = append(baseEnv, name+"="+value)
+		}
+	}
+
+	return command[0], command[1:], slices.Concat(baseEnv, env), nil
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+	"github.com/docker/mcp-gateway/pkg/hardening"
+	"github.com/docker/mcp-gateway/pkg/mounts"
+)
+
+func TestPackageSpecNpx(t *testing.T) {
+	catalogYAML := `
+package:
+  runner: npx
+  name: "@modelcontextprotocol/server-github"
+  version: 1.2.0
+command: [--toolsets, all]
+security:
+  runAsNonRoot: true
+secrets:
+  - name: github.token
+    env: GITHUB_TOKEN
+env:
+  - name: HOME
+    value: /home/github
+  `
+
+	spec := containerSpec(t, "github", catalogYAML, "", map[string]string{"github.token": "ghp_xxx"}, nil)
+
+	assert.Equal(t, "node:22-alpine", spec.Config.Image)
+	assert.Equal(t, []string{"npx", "-y", "@modelcontextprotocol/server-github@1.2.0", "--toolsets", "all"}, []string(spec.Config.Cmd))
+	assert.Equal(t, []string{"npm_config_cache=/cache", "npm_config_update_notifier=false", "HOME=/tmp", "GITHUB_TOKEN=ghp_xxx", "HOME=/home/github"}, spec.Config.Env)
+	assert.Equal(t, []string{"docker-mcp-npm-cache-github:/cache"}, spec.HostConfig.Binds)
+	assert.Empty(t, spec.Config.User)
+	assert.True(t, spec.HostConfig.ReadonlyRootfs)
+	assert.Equal(t, []string{"ALL"}, []string(spec.HostConfig.CapDrop))
+}
+
+func TestPackageSpecUvx(t *testing.T) {
+	spec := containerSpec(t, "fetch", `package: {runner: uvx, name: mcp-server-fetch, runtimeArgs: [--python, "3.12"]}`, "", nil, nil)
+
+	assert.Equal(t, "ghcr.io/astral-sh/uv:python3.12-alpine", spec.Config.Image)
+	assert.Equal(t, []string{"uvx", "--python", "3.12", "mcp-server-fetch"}, []string(spec.Config.Cmd))
+	assert.Equal(t, []string{"docker-mcp-uv-cache-fetch:/cache"}, spec.HostConfig.Binds)
+}
+
+func TestCacheVolume(t *testing.T) {
+	assert.Equal(t, "docker-mcp-npm-cache-io.github_org_server", cacheVolume(packageRuntimes[runnerNpx], "io.github/org server"))
+}
+
+func TestPackageSpecBinary(t *testing.T) {
+	spec := containerSpec(t, "local", `{package: {runner: binary, name: /opt/mcp/local-server}, security: {runAsNonRoot: true}}`, "", nil, nil)
+
+	assert.Equal(t, "debian:bookworm-slim", spec.Config.Image)
+	assert.Equal(t, []string{"/usr/local/bin/local-server"}, []string(spec.Config.Cmd))
+	assert.Equal(t, []string{"/opt/mcp/local-server:/usr/local/bin/local-server:ro"}, spec.HostConfig.Binds)
+	assert.Equal(t, hardening.NonRootUser, spec.Config.User)
+}
+
+func TestPackageSpecBinaryDeniedByMountPolicy(t *testing.T) {
+	mountPolicy, err := mounts.Parse([]byte(`deny: [/opt/mcp]`))
+	require.NoError(t, err)
+	clientPool := &clientPool{docker: &fakeImages{}, mountPolicy: mountPolicy}
+
+	_, err = clientPool.containerSpec(t.Context(), &catalog.ServerConfig{
+		Name: "local",
+		Spec: parseSpec(t, `package: {runner: binary, name: /opt/mcp/local-server}`),
+	}, nil, proxies.TargetConfig{})
+	require.ErrorContains(t, err, "server local: mounting /opt/mcp/local-server")
+}
+
+func TestPackageSpecBinaryRelativePath(t *testing.T) {
+	clientPool := &clientPool{docker: &fakeImages{}}
+
+	_, err := clientPool.containerSpec(t.Context(), &catalog.ServerConfig{
+		Name: "local",
+		Spec: parseSpec(t, `package: {runner: binary, name: bin/local-server}`),
+	}, nil, proxies.TargetConfig{})
+	require.ErrorContains(t, err, `server local: the path of the binary package "bin/local-server" must be absolute`)
+
+	_, _, _, err = clientPool.hostCommand(&catalog.ServerConfig{
+		Name: "local",
+		Spec: parseSpec(t, `package: {runner: binary, name: ./local-server}`),
+	})
+	require.ErrorContains(t, err, "must be absolute")
+}
+
+func TestPackageSpecUnknownRunner(t *testing.T) {
+	clientPool := &clientPool{docker: &fakeImages{}}
+
+	_, err := clientPool.containerSpec(t.Context(), &catalog.ServerConfig{
+		Name: "gem",
+		Spec: parseSpec(t, `package: {runner: gem, name: mcp}`),
+	}, nil, proxies.TargetConfig{})
+	require.ErrorContains(t, err, `unknown package runner "gem"`)
+}
+
+func TestPackageAllowHosts(t *testing.T) {
+	assert.Equal(t, []string{"api.github.com:443", "registry.npmjs.org:443"}, packageAllowHosts(parseSpec(t, `
+package: {runner: npx, name: github}
+allowHosts: [api.github.com:443]
+`)))
+	assert.Equal(t, []string{"api.github.com:443"}, packageAllowHosts(parseSpec(t, `
+image: mcp/github
+allowHosts: [api.github.com:443]
+`)))
+}
+
+func TestHostCommand(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("GATEWAY_TOKEN", "secret")
+	clientPool := &clientPool{}
+
+	command, args, env, err := clientPool.hostCommand(&catalog.ServerConfig{
+		Name:    "github",
+		Spec:    parseSpec(t, "package: {runner: npx, name: github}\ncommand: [--read-only]\nsecrets: [{name: github.token, env: GITHUB_TOKEN}]"),
+		Secrets: map[string]string{"github.token": "ghp_xxx"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "npx", command)
+	assert.Equal(t, []string{"-y", "github", "--read-only"}, args)
+	assert.Contains(t, env, "GITHUB_TOKEN=ghp_xxx")
+	assert.NotContains(t, env, "npm_config_cache=/cache")
+	assert.Contains(t, env, "PATH=/usr/bin")
+	assert.NotContains(t, env, "GATEWAY_TOKEN=secret")
+}
+
+func TestHostCommandWithRestrictedNetwork(t *testing.T) {
+	clientPool := &clientPool{Options: Options{BlockNetwork: true}}
+
+	_, _, _, err := clientPool.hostCommand(&catalog.ServerConfig{
+		Name: "github",
+		Spec: parseSpec(t, "package: {runner: npx, name: github}\nallowHosts: [api.github.com:443]"),
+	})
+	require.ErrorContains(t, err, "can't run on the host")
+}
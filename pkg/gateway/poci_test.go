@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+)
+
+func TestToolSpec(t *testing.T) {
+	tool := parseTool(t, `
+name: convert
+container:
+  image: alpine
+  command: [convert, '{{format}}']
+  workingDir: /data
+  env:
+    - name: FORMAT
+      value: '{{format}}'
+    - name: VERBOSE
+      value: '{{verbose}}'
+  secrets:
+    - name: convert.token
+      env: TOKEN
+    - name: convert.missing
+      env: MISSING
+  resources:
+    memory: 256Mb
+`)
+
+	clientPool := &clientPool{Options: Options{Cpus: 1, Memory: "2Gb"}}
+	spec, err := clientPool.toolSpec(tool, map[string]string{"convert.token": "secret"}, map[string]any{"format": "png"}, proxies.TargetConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "alpine", spec.Config.Image)
+	assert.Equal(t, []string{"convert", "png"}, []string(spec.Config.Cmd))
+	assert.Equal(t, "/data", spec.Config.WorkingDir)
+	assert.Equal(t, []string{"TOKEN=secret", "MISSING=<UNKNOWN>", "FORMAT=png"}, spec.Config.Env)
+	assert.Equal(t, int64(256*1024*1024), spec.HostConfig.Memory)
+	assert.Equal(t, "convert", spec.Config.Labels["docker-mcp-name"])
+}
+
+func TestToolSpecDisableNetwork(t *testing.T) {
+	tool := parseTool(t, `
+name: hash
+container:
+  image: alpine
+  disableNetwork: true
+`)
+
+	clientPool := &clientPool{networks: []string{"mcp"}}
+	spec, err := clientPool.toolSpec(tool, nil, nil, proxies.TargetConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "none", string(spec.HostConfig.NetworkMode))
+}
+
+func TestStdinContent(t *testing.T) {
+	content, err := stdinContent("hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", content)
+
+	content, err = stdinContent(map[string]any{"a": 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1}`, content)
+
+	content, err = stdinContent(nil)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func parseTool(t *testing.T, contentYAML string) catalog.Tool {
+	t.Helper()
+	var tool catalog.Tool
+	err := yaml.Unmarshal([]byte(contentYAML), &tool)
+	require.NoError(t, err)
+	return tool
+}
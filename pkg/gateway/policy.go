@@ -0,0 +1,64 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+func (g *Gateway) readPolicy() error {
+	if g.PolicyPath == "" {
+		return nil
+	}
+
+	path, err := config.FilePath(g.PolicyPath)
+	if err != nil {
+		return err
+	}
+
+	toolPolicy, err := policy.Read(path)
+	if err != nil {
+		return err
+	}
+
+	log("- Tool policy enabled:", path, fmt.Sprintf("(%d rules)", len(toolPolicy.Rules)))
+	g.policy = toolPolicy
+	return nil
+}
+
+// policyToolHandler evaluates the tool policy before forwarding a call. Like
+// profiles, the policy knows tools by the name clients see, and it checks the
+// arguments that are actually sent to the server.
+func (g *Gateway) policyToolHandler(serverName, toolName string, handler mcp.ToolHandler) mcp.ToolHandler {
+	if g.policy == nil {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := argumentsMap(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", req.Params.Name, err)
+		}
+
+		call := policy.Call{
+			Server:    serverName,
+			Tool:      toolName,
+			Arguments: arguments,
+		}
+		if params := req.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			call.Client = params.ClientInfo.Name
+		}
+
+		decision := g.policy.Evaluate(call)
+		if !decision.Allowed {
+			logf("  - Call to %s/%s %s", serverName, toolName, decision.Explanation)
+			return declinedResult(fmt.Sprintf("Calling %s is not allowed: %s", toolName, decision.Explanation)), nil
+		}
+
+		return handler(ctx, req)
+	}
+}
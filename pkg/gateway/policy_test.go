@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+func TestPolicyToolHandler(t *testing.T) {
+	ctx := t.Context()
+
+	toolPolicy, err := policy.Parse([]byte(`
+rules:
+  - action: deny
+    server: files
+    clients: [untrusted-*]
+  - action: allow
+    arguments:
+      path:
+        under: /workspace
+default: deny
+`))
+	require.NoError(t, err)
+	g := &Gateway{policy: toolPolicy}
+
+	calls := 0
+	handler := g.policyToolHandler("files", "read_file", func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "read"}}}, nil
+	})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(&mcp.Tool{Name: "read_file", InputSchema: &jsonschema.Schema{Type: "object"}}, handler)
+
+	call := func(clientName, path string) *mcp.CallToolResult {
+		client := mcp.NewClient(&mcp.Implementation{Name: clientName}, nil)
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		defer serverSession.Close()
+		session, err := client.Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		defer session.Close()
+
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "read_file", Arguments: map[string]any{"path": path}})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call("claude", "/workspace/README.md")
+	assert.False(t, result.IsError)
+	assert.Equal(t, 1, calls)
+
+	result = call("claude", "/workspace/../etc/passwd")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "denied by default")
+	assert.Equal(t, 1, calls)
+
+	result = call("untrusted-agent", "/workspace/README.md")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "denied by rule #1")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/profiles"
+)
+
+func (g *Gateway) readProfiles() error {
+	if g.ProfilesPath == "" {
+		return nil
+	}
+
+	path, err := config.FilePath(g.ProfilesPath)
+	if err != nil {
+		return err
+	}
+
+	clientProfiles, err := profiles.Read(path)
+	if err != nil {
+		return err
+	}
+
+	log("- Client profiles enabled:", path, fmt.Sprintf("(%d profiles)", len(clientProfiles.Profiles)))
+	g.profiles = clientProfiles
+	return nil
+}
+
+// profilesMiddleware restricts each session to the tools of its client's profile.
+// Tools outside the profile are hidden from tools/list and can't be called.
+// Tools are known by the name clients see, like in the policy. The gateway's
+// own tools belong to no server. Virtual tools are part of a profile when all
+// the tools they call are.
+func (g *Gateway) profilesMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/list" && method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			ss, ok := req.GetSession().(*mcp.ServerSession)
+			if !ok {
+				return next(ctx, method, req)
+			}
+			profile := g.profileForSession(ss)
+			if profile == nil {
+				return next(ctx, method, req)
+			}
+
+			if callReq, ok := req.(*mcp.CallToolRequest); ok && callReq.Params != nil {
+				if !g.profileAllows(profile, callReq.Params.Name) {
+					logf("  - Tool %s is not part of the %s profile", callReq.Params.Name, profile.Name)
+					return nil, fmt.Errorf("unknown tool %q", callReq.Params.Name)
+				}
+				return next(ctx, method, req)
+			}
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+
+			if listResult, ok := result.(*mcp.ListToolsResult); ok {
+				var tools []*mcp.Tool
+				for _, tool := range listResult.Tools {
+					if g.profileAllows(profile, tool.Name) {
+						tools = append(tools, tool)
+					}
+				}
+				listResult.Tools = tools
+			}
+
+			return result, nil
+		}
+	}
+}
+
+// profileForSession returns nil when the session's client isn't restricted.
+func (g *Gateway) profileForSession(ss *mcp.ServerSession) *profiles.Profile {
+	if g.profiles == nil || ss == nil {
+		return nil
+	}
+
+	var clientName string
+	if params := ss.InitializeParams(); params != nil && params.ClientInfo != nil {
+		clientName = params.ClientInfo.Name
+	}
+	return g.profiles.ForClient(clientName)
+}
+
+// profileAllows checks whether a registered tool is part of a profile.
+func (g *Gateway) profileAllows(profile *profiles.Profile, toolName string) bool {
+	g.toolServersMu.RLock()
+	defer g.toolServersMu.RUnlock()
+
+	if steps, isVirtual := g.virtualToolSteps[toolName]; isVirtual {
+		for _, step := range steps {
+			if !profile.Allows(step.ServerName, step.Tool.Name) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return profile.Allows(g.toolServers[toolName], toolName)
+}
@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/profiles"
+)
+
+func TestProfilesMiddleware(t *testing.T) {
+	ctx := t.Context()
+
+	clientProfiles, err := profiles.Parse([]byte(`
+profiles:
+  - name: vscode
+    clients: [vscode]
+    servers: [github-official]
+`))
+	require.NoError(t, err)
+	g := &Gateway{
+		profiles: clientProfiles,
+		toolServers: map[string]string{
+			"create_issue": "github-official",
+			"read_file":    "filesystem",
+			"triage":       "",
+			"file_issue":   "",
+		},
+		virtualToolSteps: map[string][]ToolRegistration{
+			"triage":     {{ServerName: "github-official", Tool: &mcp.Tool{Name: "create_issue"}}},
+			"file_issue": {{ServerName: "filesystem", Tool: &mcp.Tool{Name: "read_file"}}, {ServerName: "github-official", Tool: &mcp.Tool{Name: "create_issue"}}},
+		},
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddReceivingMiddleware(g.profilesMiddleware())
+	for name := range g.toolServers {
+		server.AddTool(&mcp.Tool{Name: name, InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: name}}}, nil
+		})
+	}
+
+	connect := func(clientName string) *mcp.ClientSession {
+		client := mcp.NewClient(&mcp.Implementation{Name: clientName}, nil)
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { serverSession.Close() })
+		session, err := client.Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { session.Close() })
+		return session
+	}
+	toolNames := func(session *mcp.ClientSession) []string {
+		tools, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+		require.NoError(t, err)
+		var names []string
+		for _, tool := range tools.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	// Virtual tools are part of the profile when all their steps are.
+	vscode := connect("vscode")
+	assert.ElementsMatch(t, []string{"create_issue", "triage"}, toolNames(vscode))
+	_, err = vscode.CallTool(ctx, &mcp.CallToolParams{Name: "file_issue"})
+	require.ErrorContains(t, err, `unknown tool "file_issue"`)
+	_, err = vscode.CallTool(ctx, &mcp.CallToolParams{Name: "triage"})
+	require.NoError(t, err)
+	_, err = vscode.CallTool(ctx, &mcp.CallToolParams{Name: "read_file"})
+	require.ErrorContains(t, err, `unknown tool "read_file"`)
+	_, err = vscode.CallTool(ctx, &mcp.CallToolParams{Name: "create_issue"})
+	require.NoError(t, err)
+
+	// Clients without a profile see everything.
+	other := connect("cursor")
+	assert.ElementsMatch(t, []string{"create_issue", "read_file", "triage", "file_issue"}, toolNames(other))
+	_, err = other.CallTool(ctx, &mcp.CallToolParams{Name: "read_file"})
+	require.NoError(t, err)
+}
@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// progressInterval is the minimum delay between two notifications for the
+	// same stream. Lines written in between are sent together.
+	progressInterval = 250 * time.Millisecond
+	// maxProgressLine caps the length of a line. Longer lines are split.
+	maxProgressLine = 4096
+)
+
+// toolProgress streams the output of a POCI tool to the calling client, as
+// progress notifications, when the client asked for progress.
+type toolProgress struct {
+	ctx      context.Context
+	notify   func(context.Context, *mcp.ProgressNotificationParams) error
+	token    any
+	interval time.Duration
+
+	mu       sync.Mutex
+	progress float64
+}
+
+// newToolProgress returns nil when the request has no progress token.
+func newToolProgress(ctx context.Context, req *mcp.CallToolRequest) *toolProgress {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	return &toolProgress{
+		ctx:      ctx,
+		notify:   req.Session.NotifyProgress,
+		token:    token,
+		interval: progressInterval,
+	}
+}
+
+func (p *toolProgress) send(stream string, line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.progress++
+	// Best effort: the tool keeps running even if the client went away.
+	_ = p.notify(p.ctx, &mcp.ProgressNotificationParams{
+		Meta:          mcp.Meta{"stream": stream},
+		ProgressToken: p.token,
+		Progress:      p.progress,
+		Message:       line,
+	})
+}
+
+// writer is an io.Writer that sends the complete lines written to it as
+// progress notifications, tagged with the stream they come from. Lines are
+// batched so that there's at most one notification per interval.
+func (p *toolProgress) writer(stream string) *progressWriter {
+	return &progressWriter{progress: p, stream: stream}
+}
+
+type progressWriter struct {
+	progress *toolProgress
+	stream   string
+
+	mu    sync.Mutex
+	buf   []byte   // The unterminated last line
+	lines []string // The lines not sent yet
+	sent  time.Time
+	timer *time.Timer
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	if w == nil || w.progress == nil {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		if i := bytes.IndexByte(w.buf, '\n'); i >= 0 {
+			w.lines = append(w.lines, string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+			w.buf = w.buf[i+1:]
+			continue
+		}
+		if len(w.buf) <= maxProgressLine {
+			break
+		}
+
+		// Split a line that's too long, without splitting a rune.
+		i := maxProgressLine
+		for i > 0 && !utf8.RuneStart(w.buf[i]) {
+			i--
+		}
+		w.lines = append(w.lines, string(w.buf[:i]))
+		w.buf = w.buf[i:]
+	}
+	w.sendLocked()
+
+	return len(p), nil
+}
+
+// sendLocked sends the pending lines in one notification or, if the previous
+// one is too recent, schedules it for when the interval is over.
+func (w *progressWriter) sendLocked() {
+	if len(w.lines) == 0 || w.timer != nil {
+		return
+	}
+
+	if wait := w.progress.interval - time.Since(w.sent); wait > 0 {
+		w.timer = time.AfterFunc(wait, func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+
+			w.timer = nil
+			w.sendLocked()
+		})
+		return
+	}
+
+	w.progress.send(w.stream, strings.Join(w.lines, "\n"))
+	w.lines = nil
+	w.sent = time.Now()
+}
+
+// Flush sends the pending lines and what's left of an unterminated last line.
+func (w *progressWriter) Flush() {
+	if w == nil || w.progress == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.buf) > 0 {
+		w.lines = append(w.lines, string(w.buf))
+		w.buf = nil
+	}
+	if len(w.lines) > 0 {
+		w.progress.send(w.stream, strings.Join(w.lines, "\n"))
+		w.lines = nil
+		w.sent = time.Now()
+	}
+}
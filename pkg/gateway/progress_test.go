@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressWriter(t *testing.T) {
+	var notifications []*mcp.ProgressNotificationParams
+	progress := &toolProgress{
+		ctx:   t.Context(),
+		token: "tok",
+		notify: func(_ context.Context, params *mcp.ProgressNotificationParams) error {
+			notifications = append(notifications, params)
+			return nil
+		},
+	}
+
+	stdout := progress.writer("stdout")
+	stderr := progress.writer("stderr")
+	_, _ = stdout.Write([]byte("downloading\nhalf"))
+	_, _ = stderr.Write([]byte("warning\r\n"))
+	_, _ = stdout.Write([]byte(" way\ndone"))
+	stdout.Flush()
+	stderr.Flush()
+
+	assert.Equal(t, []*mcp.ProgressNotificationParams{
+		{Meta: mcp.Meta{"stream": "stdout"}, ProgressToken: "tok", Progress: 1, Message: "downloading"},
+		{Meta: mcp.Meta{"stream": "stderr"}, ProgressToken: "tok", Progress: 2, Message: "warning"},
+		{Meta: mcp.Meta{"stream": "stdout"}, ProgressToken: "tok", Progress: 3, Message: "half way"},
+		{Meta: mcp.Meta{"stream": "stdout"}, ProgressToken: "tok", Progress: 4, Message: "done"},
+	}, notifications)
+}
+
+func TestProgressWriterBatchesLines(t *testing.T) {
+	var messages []string
+	progress := &toolProgress{
+		ctx:      t.Context(),
+		token:    "tok",
+		interval: time.Hour,
+		notify: func(_ context.Context, params *mcp.ProgressNotificationParams) error {
+			messages = append(messages, params.Message)
+			return nil
+		},
+	}
+
+	stdout := progress.writer("stdout")
+	_, _ = stdout.Write([]byte("1\n2\n"))
+	_, _ = stdout.Write([]byte("3\n4"))
+	stdout.Flush()
+
+	// The first notification goes right away, the others wait for the interval or the end.
+	assert.Equal(t, []string{"1\n2", "3\n4"}, messages)
+}
+
+func TestProgressWriterLongLine(t *testing.T) {
+	var messages []string
+	progress := &toolProgress{
+		ctx:   t.Context(),
+		token: "tok",
+		notify: func(_ context.Context, params *mcp.ProgressNotificationParams) error {
+			messages = append(messages, params.Message)
+			return nil
+		},
+	}
+
+	stdout := progress.writer("stdout")
+	_, _ = stdout.Write([]byte(strings.Repeat("a", maxProgressLine-1) + "é" + strings.Repeat("b", 10)))
+
+	// The line is split before the rune that doesn't fit.
+	assert.Equal(t, []string{strings.Repeat("a", maxProgressLine-1)}, messages)
+	assert.Equal(t, []byte("é"+strings.Repeat("b", 10)), stdout.buf)
+}
+
+func TestProgressWriterWithoutProgress(t *testing.T) {
+	var progress *toolProgress
+
+	n, err := progress.writer("stdout").Write([]byte("output\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, n)
+}
+
+func TestNewToolProgressWithoutToken(t *testing.T) {
+	assert.Nil(t, newToolProgress(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{}}))
+}
+
+func TestToolResult(t *testing.T) {
+	exitCode := int64(0)
+	result := toolResult("ok", "noise", &exitCode)
+	assert.False(t, result.IsError)
+	assert.Equal(t, mcp.Meta{"exitCode": int64(0)}, result.Meta)
+	assert.Len(t, result.Content, 1)
+
+	exitCode = 2
+	result = toolResult("partial", "boom", &exitCode)
+	assert.True(t, result.IsError)
+	assert.Equal(t, mcp.Meta{"exitCode": int64(2)}, result.Meta)
+	assert.Equal(t, []mcp.Content{&mcp.TextContent{Text: "partial"}, &mcp.TextContent{Text: "boom"}}, result.Content)
+
+	result = toolResult("timed out", "", nil)
+	assert.True(t, result.IsError)
+	assert.Nil(t, result.Meta)
+	assert.Len(t, result.Content, 1)
+}
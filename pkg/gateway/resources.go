@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// serverResources are the resources of a server's spec, overridden by the
+// resources key of its config.
+func serverResources(serverConfig *catalog.ServerConfig) (catalog.Resources, error) {
+	resources := serverConfig.Spec.Resources
+
+	serverValues, ok := serverConfig.Config[oci.CanonicalizeServerName(serverConfig.Name)].(map[string]any)
+	if !ok || serverValues["resources"] == nil {
+		return resources, nil
+	}
+
+	buf, err := yaml.Marshal(serverValues["resources"])
+	if err != nil {
+		return catalog.Resources{}, err
+	}
+	var override catalog.Resources
+	if err := yaml.Unmarshal(buf, &override); err != nil {
+		return catalog.Resources{}, fmt.Errorf("invalid resources in the config of %s: %w", serverConfig.Name, err)
+	}
+
+	if override.CPUs != 0 {
+		resources.CPUs = override.CPUs
+	}
+	if override.Memory != "" {
+		resources.Memory = override.Memory
+	}
+	if override.Pids != 0 {
+		resources.Pids = override.Pids
+	}
+	if len(override.Ulimits) > 0 {
+		resources.Ulimits = override.Ulimits
+	}
+	if override.Runtime != "" {
+		resources.Runtime = override.Runtime
+	}
+
+	return resources, nil
+}
+
+// applyResources applies the resources of a server to its container. CPUs and
+// memory can't go above the global --cpus and --memory.
+func (cp *clientPool) applyResources(spec *docker.ContainerSpec, resources catalog.Resources) error {
+	if resources.CPUs > 0 {
+		nanoCPUs := int64(resources.CPUs * 1e9)
+		if cp.Cpus <= 0 || nanoCPUs < spec.HostConfig.NanoCPUs {
+			spec.HostConfig.NanoCPUs = nanoCPUs
+		}
+	}
+
+	if resources.Memory != "" {
+		memory, err := units.RAMInBytes(resources.Memory)
+		if err != nil {
+			return fmt.Errorf("invalid memory limit %q: %w", resources.Memory, err)
+		}
+		if cp.Memory == "" || memory < spec.HostConfig.Memory {
+			spec.HostConfig.Memory = memory
+		}
+	}
+
+	if resources.Pids > 0 {
+		pids := resources.Pids
+		spec.HostConfig.PidsLimit = &pids
+	}
+
+	for _, value := range resources.Ulimits {
+		ulimit, err := units.ParseUlimit(value)
+		if err != nil {
+			return err
+		}
+		spec.HostConfig.Ulimits = append(spec.HostConfig.Ulimits, ulimit)
+	}
+
+	spec.HostConfig.Runtime = resources.Runtime
+
+	return nil
+}
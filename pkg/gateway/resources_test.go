@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+)
+
+func TestResourcesFromSpec(t *testing.T) {
+	catalogYAML := `
+resources:
+  cpus: 0.5
+  memory: 512Mb
+  pids: 1024
+  ulimits:
+    - nofile=1024:2048
+  runtime: runsc
+  `
+
+	spec := containerSpec(t, "time", catalogYAML, "", nil, nil)
+
+	assert.Equal(t, int64(5e8), spec.HostConfig.NanoCPUs)
+	assert.Equal(t, int64(512*1024*1024), spec.HostConfig.Memory)
+	require.NotNil(t, spec.HostConfig.PidsLimit)
+	assert.Equal(t, int64(1024), *spec.HostConfig.PidsLimit)
+	require.Len(t, spec.HostConfig.Ulimits, 1)
+	assert.Equal(t, "nofile", spec.HostConfig.Ulimits[0].Name)
+	assert.Equal(t, int64(1024), spec.HostConfig.Ulimits[0].Soft)
+	assert.Equal(t, int64(2048), spec.HostConfig.Ulimits[0].Hard)
+	assert.Equal(t, "runsc", spec.HostConfig.Runtime)
+}
+
+func TestResourcesOverriddenInConfig(t *testing.T) {
+	catalogYAML := `
+resources:
+  cpus: 0.5
+  memory: 512Mb
+  `
+	configYAML := `
+browser:
+  resources:
+    memory: 1Gb
+    runtime: runsc
+`
+
+	spec := containerSpec(t, "browser", catalogYAML, configYAML, nil, nil)
+
+	assert.Equal(t, int64(5e8), spec.HostConfig.NanoCPUs)
+	assert.Equal(t, int64(1024*1024*1024), spec.HostConfig.Memory)
+	assert.Equal(t, "runsc", spec.HostConfig.Runtime)
+}
+
+func TestResourcesCappedByGlobalLimits(t *testing.T) {
+	catalogYAML := `
+resources:
+  cpus: 4
+  memory: 8Gb
+  `
+
+	spec := containerSpec(t, "browser", catalogYAML, "", nil, nil)
+
+	assert.Equal(t, int64(1e9), spec.HostConfig.NanoCPUs)
+	assert.Equal(t, int64(2*1024*1024*1024), spec.HostConfig.Memory)
+}
+
+func TestResourcesInvalidUlimit(t *testing.T) {
+	clientPool := &clientPool{docker: &fakeImages{}}
+
+	_, err := clientPool.containerSpec(t.Context(), &catalog.ServerConfig{
+		Name: "svc",
+		Spec: parseSpec(t, "resources: {ulimits: [nofile]}"),
+	}, nil, proxies.TargetConfig{})
+
+	require.Error(t, err)
+}
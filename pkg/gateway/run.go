@@ -14,16 +14,24 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel"
 
+	"github.com/docker/mcp-gateway/pkg/audit"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/health"
 	"github.com/docker/mcp-gateway/pkg/interceptors"
+	"github.com/docker/mcp-gateway/pkg/overrides"
+	"github.com/docker/mcp-gateway/pkg/policy"
+	"github.com/docker/mcp-gateway/pkg/profiles"
+	"github.com/docker/mcp-gateway/pkg/results"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
+	"github.com/docker/mcp-gateway/pkg/virtualtools"
 )
 
 const TokenEventFilename = "token-event.json"
 
 type ServerSessionCache struct {
 	Roots []*mcp.Root
+	// Tools the user always allows for this session, keyed by server/tool
+	AllowedTools map[string]bool
 }
 
 // TokenEvent represents a token refresh or acquisition event
@@ -47,6 +55,8 @@ type TokenEvent struct {
 // ss     *mcp.ServerSession
 // }
 
+const gatewayVersion = "2.0.1"
+
 type Gateway struct {
 	Options
 	docker        docker.Client
@@ -55,6 +65,12 @@ type Gateway struct {
 	clientPool    *clientPool
 	mcpServer     *mcp.Server
 	health        health.State
+	audit         *audit.Logger
+	policy        *policy.Policy
+	profiles      *profiles.Profiles
+	overrides     *overrides.Overrides
+	results       *results.Store
+	virtualTools  *virtualtools.Config
 	// subsChannel  chan SubsMessage
 
 	sessionCacheMu sync.RWMutex
@@ -65,6 +81,12 @@ type Gateway struct {
 	registeredPromptNames          []string
 	registeredResourceURIs         []string
 	registeredResourceTemplateURIs []string
+
+	// Which server provides each registered tool, and which tools each
+	// virtual tool calls
+	toolServersMu    sync.RWMutex
+	toolServers      map[string]string
+	virtualToolSteps map[string][]ToolRegistration
 }
 
 func NewGateway(config Config, docker docker.Client) *Gateway {
@@ -78,7 +100,10 @@ func NewGateway(config Config, docker docker.Client) *Gateway {
 			ConfigPath:         config.ConfigPath,
 			SecretsPath:        config.SecretsPath,
 			ToolsPath:          config.ToolsPath,
+			PromptsPath:        config.PromptsPath,
+			ResourcesPath:      config.ResourcesPath,
 			OciRef:             config.OciRef,
+			MCPRegistryPrefer:  config.MCPRegistryPrefer,
 			MCPRegistryServers: config.MCPRegistryServers,
 			Watch:              config.Watch,
 			Central:            config.Central,
@@ -150,12 +175,48 @@ func (g *Gateway) Run(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("parsing interceptors: %w", err)
 		}
+		for i := range parsedInterceptors {
+			parsedInterceptors[i].Docker = g.docker
+		}
 		log("- Interceptors enabled:", strings.Join(g.Interceptors, ", "))
 	}
 
+	// Open the audit log
+	if err := g.openAuditLog(); err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	if g.audit != nil {
+		defer g.audit.Close()
+	}
+
+	// Read the tool policy
+	if err := g.readPolicy(); err != nil {
+		return fmt.Errorf("reading tool policy: %w", err)
+	}
+
+	// Read the mount policy
+	if err := g.readMountPolicy(); err != nil {
+		return fmt.Errorf("reading mount policy: %w", err)
+	}
+
+	// Read the tool overrides
+	if err := g.readToolOverrides(); err != nil {
+		return fmt.Errorf("reading tool overrides: %w", err)
+	}
+
+	// Read the virtual tools
+	if err := g.readVirtualTools(); err != nil {
+		return fmt.Errorf("reading virtual tools: %w", err)
+	}
+
+	// Read the client profiles
+	if err := g.readProfiles(); err != nil {
+		return fmt.Errorf("reading client profiles: %w", err)
+	}
+
 	g.mcpServer = mcp.NewServer(&mcp.Implementation{
 		Name:    "Docker AI MCP Gateway",
-		Version: "2.0.1",
+		Version: gatewayVersion,
 	}, &mcp.ServerOptions{
 		SubscribeHandler: func(_ context.Context, req *mcp.SubscribeRequest) error {
 			log("- Client subscribed to URI:", req.Params.URI)
@@ -173,13 +234,21 @@ func (g *Gateway) Run(ctx context.Context) error {
 		},
 		RootsListChangedHandler: func(ctx context.Context, req *mcp.RootsListChangedRequest) {
 			log("- Client roots list changed")
-			// We can't get the ServerSession from the request anymore, so we'll need to handle this differently
-			_, _ = req.Session.ListRoots(ctx, &mcp.ListRootsParams{})
+			go g.ListRoots(context.WithoutCancel(ctx), req.Session)
 		},
 		CompletionHandler: nil,
-		InitializedHandler: func(_ context.Context, req *mcp.InitializedRequest) {
+		InitializedHandler: func(ctx context.Context, req *mcp.InitializedRequest) {
 			clientInfo := req.Session.InitializeParams().ClientInfo
 			log(fmt.Sprintf("- Client initialized %s@%s %s", clientInfo.Name, clientInfo.Version, clientInfo.Title))
+			if g.profiles != nil {
+				if profile := g.profileForSession(req.Session); profile != nil {
+					log("  - Using the", profile.Name, "profile")
+				}
+			}
+			// Servers that need workspace access get the roots mounted into their containers.
+			// Listing them is a request to the client: don't block the session while it answers.
+			go g.ListRoots(context.WithoutCancel(ctx), req.Session)
+			go g.forgetSessionWhenClosed(req.Session)
 		},
 		HasPrompts:   true,
 		HasResources: true,
@@ -188,13 +257,32 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 	// Add interceptor middleware to the server (includes telemetry)
 	middlewares := interceptors.Callbacks(g.LogCalls, g.BlockSecrets, g.OAuthInterceptorEnabled, parsedInterceptors)
+	if g.profiles != nil {
+		middlewares = append(middlewares, g.profilesMiddleware())
+	}
 	if len(middlewares) > 0 {
 		g.mcpServer.AddReceivingMiddleware(middlewares...)
 	}
 
+	// Keep large tool results aside
+	if err := g.openResultsStore(); err != nil {
+		return fmt.Errorf("opening the store of tool results: %w", err)
+	}
+	if g.results != nil {
+		defer g.results.Close()
+	}
+
+	if g.RecordDir != "" {
+		log("- Recording sessions with MCP servers to", g.RecordDir)
+	}
+	if g.ReplayDir != "" {
+		log("- Replaying sessions with MCP servers from", g.ReplayDir)
+	}
+
 	// Which docker images are used?
 	// Pull them and verify them if possible.
-	if !g.Static {
+	// When replaying recorded sessions, no image is needed.
+	if !g.Static && g.ReplayDir == "" {
 		if err := g.pullAndVerify(ctx, configuration); err != nil {
 			return err
 		}
@@ -324,39 +412,39 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 	g.registeredResourceTemplateURIs = nil
 
 	// Add new capabilities and track them
-	for _, tool := range capabilities.Tools {
-		g.mcpServer.AddTool(tool.Tool, tool.Handler)
+	toolServers := map[string]string{}
+	virtualToolSteps := map[string][]ToolRegistration{}
+	serverTools := rejectRenameCollisions(capabilities.Tools)
+	tools := g.enforceToolsBudget(append(serverTools, g.virtualToolRegistrations(serverTools)...))
+	for _, tool := range tools {
+		g.mcpServer.AddTool(withServerMeta(tool), g.offloadToolHandler(tool.Handler))
 		g.registeredToolNames = append(g.registeredToolNames, tool.Tool.Name)
+		toolServers[tool.Tool.Name] = tool.ServerName
+		if tool.steps != nil {
+			virtualToolSteps[tool.Tool.Name] = tool.steps
+		}
 	}
+	g.toolServersMu.Lock()
+	g.toolServers = toolServers
+	g.virtualToolSteps = virtualToolSteps
+	g.toolServersMu.Unlock()
 
 	// Add internal tools when dynamic-tools feature is enabled
 	if g.DynamicTools {
 		log("- Adding internal tools (dynamic-tools feature enabled)")
 
-		// Add mcp-find tool
-		mcpFindTool := g.createMcpFindTool(configuration)
-		g.mcpServer.AddTool(mcpFindTool.Tool, mcpFindTool.Handler)
-		g.registeredToolNames = append(g.registeredToolNames, mcpFindTool.Tool.Name)
-
-		// Add mcp-add tool
-		mcpAddTool := g.createMcpAddTool(configuration, clientConfig)
-		g.mcpServer.AddTool(mcpAddTool.Tool, mcpAddTool.Handler)
-		g.registeredToolNames = append(g.registeredToolNames, mcpAddTool.Tool.Name)
-
-		// Add mcp-remove tool
-		mcpRemoveTool := g.createMcpRemoveTool(configuration, clientConfig)
-		g.mcpServer.AddTool(mcpRemoveTool.Tool, mcpRemoveTool.Handler)
-		g.registeredToolNames = append(g.registeredToolNames, mcpRemoveTool.Tool.Name)
-
-		// Add mcp-registry-import tool
-		mcpRegistryImportTool := g.createMcpRegistryImportTool(configuration, clientConfig)
-		g.mcpServer.AddTool(mcpRegistryImportTool.Tool, mcpRegistryImportTool.Handler)
-		g.registeredToolNames = append(g.registeredToolNames, mcpRegistryImportTool.Tool.Name)
-
-		// Add mcp-config-set tool
-		mcpConfigSetTool := g.createMcpConfigSetTool(configuration, clientConfig)
-		g.mcpServer.AddTool(mcpConfigSetTool.Tool, mcpConfigSetTool.Handler)
-		g.registeredToolNames = append(g.registeredToolNames, mcpConfigSetTool.Tool.Name)
+		// The internal tools are audited like the tools of the servers, without a server name.
+		internalTools := []*ToolRegistration{
+			g.createMcpFindTool(configuration),
+			g.createMcpAddTool(configuration, clientConfig),
+			g.createMcpRemoveTool(configuration, clientConfig),
+			g.createMcpRegistryImportTool(configuration, clientConfig),
+			g.createMcpConfigSetTool(configuration, clientConfig),
+		}
+		for _, tool := range internalTools {
+			g.mcpServer.AddTool(tool.Tool, g.auditToolHandler("", tool.Handler))
+			g.registeredToolNames = append(g.registeredToolNames, tool.Tool.Name)
+		}
 
 		log("  > mcp-find: tool for finding MCP servers in the catalog")
 		log("  > mcp-add: tool for adding MCP servers to the registry")
@@ -375,17 +463,10 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		g.registeredResourceURIs = append(g.registeredResourceURIs, resource.Resource.URI)
 	}
 
-	// Resource templates are handled as regular resources in the new SDK
 	for _, template := range capabilities.ResourceTemplates {
-		// Convert ResourceTemplate to Resource
-		resource := &mcp.ResourceTemplate{
-			URITemplate: template.ResourceTemplate.URITemplate,
-			Name:        template.ResourceTemplate.Name,
-			Description: template.ResourceTemplate.Description,
-			MIMEType:    template.ResourceTemplate.MIMEType,
-		}
-		g.mcpServer.AddResourceTemplate(resource, template.Handler)
-		g.registeredResourceTemplateURIs = append(g.registeredResourceTemplateURIs, resource.URITemplate)
+		resourceTemplate := template.ResourceTemplate
+		g.mcpServer.AddResourceTemplate(&resourceTemplate, template.Handler)
+		g.registeredResourceTemplateURIs = append(g.registeredResourceTemplateURIs, resourceTemplate.URITemplate)
 	}
 
 	g.health.SetHealthy()
@@ -439,6 +520,17 @@ func (g *Gateway) RemoveSessionCache(ss *mcp.ServerSession) {
 	delete(g.sessionCache, ss)
 }
 
+// forgetSessionWhenClosed removes what the gateway keeps for a session, once
+// the session is closed.
+func (g *Gateway) forgetSessionWhenClosed(ss *mcp.ServerSession) {
+	_ = ss.Wait()
+
+	g.RemoveSessionCache(ss)
+	if g.results != nil {
+		g.results.RemoveSession(sessionID(ss))
+	}
+}
+
 // ListRoots checks if client supports Roots, gets them, and caches the result
 func (g *Gateway) ListRoots(ctx context.Context, ss *mcp.ServerSession) {
 	// Check if client supports Roots and get them if available
@@ -0,0 +1,219 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// validateToolHandler checks the arguments of a call against the tool's input
+// schema before the call is forwarded. With --coerce-arguments, simple values
+// are converted to the expected type first, eg. "5" into 5.
+func (g *Gateway) validateToolHandler(tool *mcp.Tool, handler mcp.ToolHandler) mcp.ToolHandler {
+	if !g.ValidateArguments && !g.CoerceArguments {
+		return handler
+	}
+
+	validator, err := newArgumentsValidator(tool.InputSchema)
+	if err != nil {
+		logf("  > Can't validate the arguments of %s: %s", tool.Name, err)
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := argumentsMap(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", req.Params.Name, err)
+		}
+
+		if g.CoerceArguments && validator.coerce(arguments) {
+			params := *req.Params
+			params.Arguments = arguments
+			req = &mcp.CallToolRequest{Session: req.Session, Params: &params, Extra: req.Extra}
+		}
+
+		if g.ValidateArguments {
+			if violations := validator.violations(arguments); len(violations) > 0 {
+				logf("  - Invalid arguments for %s: %s", req.Params.Name, strings.Join(violations, ", "))
+				return invalidArgumentsResult(req.Params.Name, violations), nil
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+type argumentsValidator struct {
+	schema *jsonschema.Schema
+	full   *jsonschema.Resolved
+	// Each property is also validated on its own, so that every violation is reported.
+	properties map[string]*jsonschema.Resolved
+}
+
+func newArgumentsValidator(schema *jsonschema.Schema) (*argumentsValidator, error) {
+	if schema == nil {
+		return nil, errors.New("no input schema")
+	}
+
+	// Many servers declare an older draft but the validator only knows 2020-12.
+	root := *schema
+	root.Schema = ""
+	full, err := root.Resolve(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]*jsonschema.Resolved{}
+	for name, property := range schema.Properties {
+		if property == nil {
+			continue
+		}
+		resolved, err := (&jsonschema.Schema{
+			Type:        "object",
+			Properties:  map[string]*jsonschema.Schema{name: property},
+			Defs:        schema.Defs,
+			Definitions: schema.Definitions,
+		}).Resolve(nil)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = resolved
+	}
+
+	return &argumentsValidator{
+		schema:     &root,
+		full:       full,
+		properties: properties,
+	}, nil
+}
+
+// violations lists, in a way that a model can act upon, why the arguments don't
+// match the schema.
+func (v *argumentsValidator) violations(arguments map[string]any) []string {
+	var violations []string
+
+	for _, name := range v.schema.Required {
+		if _, found := arguments[name]; !found {
+			violations = append(violations, fmt.Sprintf("missing required argument %q", name))
+		}
+	}
+
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resolved, found := v.properties[name]
+		if !found {
+			if v.schema.AdditionalProperties != nil && v.schema.AdditionalProperties.Not != nil {
+				violations = append(violations, fmt.Sprintf("unknown argument %q", name))
+			}
+			continue
+		}
+		if err := resolved.Validate(map[string]any{name: arguments[name]}); err != nil {
+			violations = append(violations, fmt.Sprintf("argument %q: %s", name, rootCause(err)))
+		}
+	}
+
+	// Catch what can't be checked property by property, like oneOf at the root.
+	if len(violations) == 0 {
+		if err := v.full.Validate(arguments); err != nil {
+			violations = append(violations, rootCause(err).Error())
+		}
+	}
+
+	return violations
+}
+
+// coerce converts, in place, string arguments into the number or boolean the
+// schema expects, and numbers or booleans into expected strings. It reports
+// whether something was converted.
+func (v *argumentsValidator) coerce(arguments map[string]any) bool {
+	coerced := false
+
+	for name, value := range arguments {
+		property, found := v.schema.Properties[name]
+		if !found || property == nil {
+			continue
+		}
+
+		types := property.Types
+		if property.Type != "" {
+			types = []string{property.Type}
+		}
+		if len(types) != 1 {
+			continue
+		}
+
+		if converted, ok := coerceValue(value, types[0]); ok {
+			arguments[name] = converted
+			coerced = true
+		}
+	}
+
+	return coerced
+}
+
+func coerceValue(value any, expectedType string) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		switch expectedType {
+		case "integer":
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i, true
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, true
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b, true
+			}
+		}
+	case float64:
+		if expectedType == "string" {
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		}
+	case bool:
+		if expectedType == "string" {
+			return strconv.FormatBool(v), true
+		}
+	}
+
+	return nil, false
+}
+
+// rootCause drops the "validating <schema>" prefixes the validator adds at each level.
+func rootCause(err error) error {
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return err
+		}
+		err = unwrapped
+	}
+}
+
+func invalidArgumentsResult(toolName string, violations []string) *mcp.CallToolResult {
+	var message strings.Builder
+	fmt.Fprintf(&message, "Invalid arguments for %s:", toolName)
+	for _, violation := range violations {
+		message.WriteString("\n- " + violation)
+	}
+	message.WriteString("\nFix the arguments to match the tool's input schema and try again.")
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: message.String()}},
+		IsError: true,
+	}
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchTool() *mcp.Tool {
+	var schema jsonschema.Schema
+	_ = json.Unmarshal([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "minLength": 1},
+			"limit": {"type": "integer", "maximum": 100},
+			"exact": {"type": "boolean"}
+		},
+		"required": ["query"],
+		"additionalProperties": false
+	}`), &schema)
+	return &mcp.Tool{Name: "search", InputSchema: &schema}
+}
+
+func TestViolations(t *testing.T) {
+	validator, err := newArgumentsValidator(searchTool().InputSchema)
+	require.NoError(t, err)
+
+	assert.Empty(t, validator.violations(map[string]any{"query": "mcp", "limit": 10.0}))
+	violations := validator.violations(map[string]any{"limit": 500.0, "exact": "yes", "page": 2.0})
+	require.Len(t, violations, 4)
+	assert.Equal(t, `missing required argument "query"`, violations[0])
+	assert.Equal(t, `argument "exact": type: yes has type "string", want "boolean"`, violations[1])
+	assert.Contains(t, violations[2], `argument "limit": maximum:`)
+	assert.Equal(t, `unknown argument "page"`, violations[3])
+}
+
+func TestCoerce(t *testing.T) {
+	validator, err := newArgumentsValidator(searchTool().InputSchema)
+	require.NoError(t, err)
+
+	arguments := map[string]any{"query": 42.0, "limit": "5", "exact": "true"}
+	assert.True(t, validator.coerce(arguments))
+	assert.Equal(t, map[string]any{"query": "42", "limit": int64(5), "exact": true}, arguments)
+	assert.Empty(t, validator.violations(arguments))
+
+	arguments = map[string]any{"query": "mcp", "limit": "five"}
+	assert.False(t, validator.coerce(arguments))
+	assert.Equal(t, "five", arguments["limit"])
+}
+
+func TestValidateToolHandler(t *testing.T) {
+	g := &Gateway{Options: Options{ValidateArguments: true, CoerceArguments: true}}
+
+	var forwarded any
+	handler := g.validateToolHandler(searchTool(), func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		forwarded = req.Params.Arguments
+		return &mcp.CallToolResult{}, nil
+	})
+
+	result, err := handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{Name: "search", Arguments: map[string]any{"limit": "5"}}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `missing required argument "query"`)
+	assert.Nil(t, forwarded)
+
+	result, err = handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{Name: "search", Arguments: map[string]any{"query": "mcp", "limit": "5"}}})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, map[string]any{"query": "mcp", "limit": int64(5)}, forwarded)
+}
@@ -0,0 +1,204 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+	"github.com/docker/mcp-gateway/pkg/virtualtools"
+)
+
+func (g *Gateway) readVirtualTools() error {
+	if g.VirtualToolsPath == "" {
+		return nil
+	}
+
+	path, err := config.FilePath(g.VirtualToolsPath)
+	if err != nil {
+		return err
+	}
+
+	virtualTools, err := virtualtools.Read(path)
+	if err != nil {
+		return err
+	}
+
+	log("- Virtual tools enabled:", path, fmt.Sprintf("(%d tools)", len(virtualTools.Tools)))
+	g.virtualTools = virtualTools
+	return nil
+}
+
+// virtualToolRegistrations binds each step of the virtual tools to one of the
+// available tools. Virtual tools with a step that can't be bound, or with the
+// name of an available tool, are skipped.
+func (g *Gateway) virtualToolRegistrations(tools []ToolRegistration) []ToolRegistration {
+	if g.virtualTools == nil {
+		return nil
+	}
+
+	var registrations []ToolRegistration
+	for _, virtualTool := range g.virtualTools.Tools {
+		if i := slices.IndexFunc(tools, func(tool ToolRegistration) bool { return tool.Tool.Name == virtualTool.Name }); i >= 0 {
+			logf("  > Can't register virtual tool %s: %s already has a tool with that name", virtualTool.Name, tools[i].ServerName)
+			continue
+		}
+
+		steps, err := bindSteps(virtualTool, tools)
+		if err != nil {
+			logf("  > Can't register virtual tool %s: %s", virtualTool.Name, err)
+			continue
+		}
+
+		tool := &mcp.Tool{
+			Name:        virtualTool.Name,
+			Title:       virtualTool.Title,
+			Description: virtualTool.Description,
+			InputSchema: virtualTool.Schema(),
+		}
+		// Each step is audited on its own, as well as the virtual tool as a whole.
+		registrations = append(registrations, ToolRegistration{
+			Tool:    tool,
+			Handler: g.auditToolHandler("", g.validateToolHandler(tool, g.virtualToolHandler(virtualTool, steps))),
+			steps:   steps,
+		})
+		logf("  > %s: virtual tool with %d steps", virtualTool.Name, len(virtualTool.Steps))
+	}
+
+	return registrations
+}
+
+// bindSteps finds the tool each step of a virtual tool calls.
+func bindSteps(virtualTool virtualtools.Tool, tools []ToolRegistration) ([]ToolRegistration, error) {
+	steps := make([]ToolRegistration, len(virtualTool.Steps))
+	for i, step := range virtualTool.Steps {
+		// When several servers have the same tool, the last one wins, like when tools are registered.
+		for _, tool := range tools {
+			if tool.Tool.Name == step.Tool && (step.Server == "" || step.Server == tool.ServerName) {
+				steps[i] = tool
+			}
+		}
+		if steps[i].Handler == nil {
+			return nil, fmt.Errorf("%s: tool not found", virtualTool.StepLabel(i))
+		}
+	}
+
+	return steps, nil
+}
+
+// virtualToolHandler runs the steps of a virtual tool one after the other. The
+// first step that fails stops the virtual tool. Steps are restricted to the
+// profile of the session, like the calls made by the client itself.
+func (g *Gateway) virtualToolHandler(virtualTool virtualtools.Tool, tools []ToolRegistration) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		input, err := argumentsMap(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", req.Params.Name, err)
+		}
+
+		// No step runs unless they are all part of the session's profile.
+		if profile := g.profileForSession(req.Session); profile != nil {
+			for i, step := range virtualTool.Steps {
+				if !profile.Allows(tools[i].ServerName, step.Tool) {
+					logf("  - Virtual tool %s can't run %s: it's not part of the %s profile", virtualTool.Name, virtualTool.StepLabel(i), profile.Name)
+					return declinedResult(fmt.Sprintf("%s can't run %s: %s is not part of the %s profile", virtualTool.Name, virtualTool.StepLabel(i), step.Tool, profile.Name)), nil
+				}
+			}
+		}
+
+		steps := map[string]any{}
+		variables := map[string]any{"input": input, "steps": steps}
+
+		var result *mcp.CallToolResult
+		for i, step := range virtualTool.Steps {
+			arguments := virtualtools.Evaluate(step.Arguments, variables)
+
+			stepCtx, span := telemetry.StartVirtualToolStepSpan(ctx, virtualTool.Name, i+1, step.Tool)
+			start := time.Now()
+			result, err = tools[i].Handler(stepCtx, &mcp.CallToolRequest{
+				Session: req.Session,
+				Params:  &mcp.CallToolParams{Name: step.Tool, Arguments: arguments},
+				Extra:   req.Extra,
+			})
+			failed := err != nil || result == nil || result.IsError
+			if failed {
+				span.SetStatus(codes.Error, "Step failed")
+			}
+			span.End()
+			telemetry.RecordVirtualToolStep(ctx, virtualTool.Name, i+1, step.Tool, float64(time.Since(start).Milliseconds()), !failed)
+
+			if failed {
+				reason := "no result"
+				switch {
+				case err != nil:
+					reason = err.Error()
+				case result != nil:
+					reason = resultText(result)
+				}
+				logf("  - Virtual tool %s failed at %s: %s", virtualTool.Name, virtualTool.StepLabel(i), reason)
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s failed at %s, after %d successful step(s): %s", virtualTool.Name, virtualTool.StepLabel(i), i, reason)}},
+					IsError: true,
+				}, nil
+			}
+
+			steps[step.StepKey(i)] = stepVariables(result)
+		}
+
+		if virtualTool.Result == "" {
+			return result, nil
+		}
+
+		value := virtualtools.Evaluate(virtualTool.Result, variables)
+		text, ok := value.(string)
+		if !ok {
+			buf, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			text = string(buf)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+	}
+}
+
+// stepVariables exposes the result of a step to the following steps: as text,
+// and as JSON when the result is structured or its text is JSON.
+func stepVariables(result *mcp.CallToolResult) map[string]any {
+	text := resultText(result)
+	variables := map[string]any{"text": text}
+
+	if result.StructuredContent != nil {
+		if buf, err := json.Marshal(result.StructuredContent); err == nil {
+			var value any
+			if err := json.Unmarshal(buf, &value); err == nil {
+				variables["json"] = value
+			}
+		}
+	}
+	if _, found := variables["json"]; !found {
+		var value any
+		if err := json.Unmarshal([]byte(text), &value); err == nil {
+			variables["json"] = value
+		}
+	}
+
+	return variables
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/profiles"
+	"github.com/docker/mcp-gateway/pkg/virtualtools"
+)
+
+func TestVirtualTool(t *testing.T) {
+	virtualTools, err := virtualtools.Parse([]byte(`
+tools:
+  - name: triage_issue
+    steps:
+      - id: issue
+        tool: get_issue
+        arguments:
+          number: "{{input.number}}"
+      - id: code
+        tool: search_code
+        arguments:
+          q: "{{steps.issue.json.title}}"
+    result: "{{steps.issue.json.title}}: {{steps.code.text}}"
+  - name: missing_step
+    steps:
+      - tool: unknown
+`))
+	require.NoError(t, err)
+	g := &Gateway{virtualTools: virtualTools}
+
+	var calls []*mcp.CallToolParams
+	searchFails := false
+	tools := []ToolRegistration{
+		{
+			ServerName: "github-official",
+			Tool:       &mcp.Tool{Name: "get_issue", InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls = append(calls, req.Params)
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: `{"title": "Crash on start"}`}}}, nil
+			},
+		},
+		{
+			ServerName: "github-official",
+			Tool:       &mcp.Tool{Name: "search_code", InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls = append(calls, req.Params)
+				if searchFails {
+					return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "rate limited"}}, IsError: true}, nil
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "main.go"}}}, nil
+			},
+		},
+	}
+
+	registrations := g.virtualToolRegistrations(tools)
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "triage_issue", registrations[0].Tool.Name)
+	assert.Empty(t, registrations[0].ServerName)
+
+	call := func() *mcp.CallToolResult {
+		result, err := registrations[0].Handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParams{
+			Name:      "triage_issue",
+			Arguments: map[string]any{"number": 42},
+		}})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call()
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Crash on start: main.go", result.Content[0].(*mcp.TextContent).Text)
+	require.Len(t, calls, 2)
+	assert.Equal(t, map[string]any{"number": 42}, calls[0].Arguments)
+	assert.Equal(t, map[string]any{"q": "Crash on start"}, calls[1].Arguments)
+
+	searchFails = true
+	result = call()
+	assert.True(t, result.IsError)
+	assert.Equal(t, "triage_issue failed at step 2 (code: search_code), after 1 successful step(s): rate limited", result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestVirtualToolNameCollision(t *testing.T) {
+	virtualTools, err := virtualtools.Parse([]byte(`
+tools:
+  - name: get_issue
+    steps:
+      - tool: search_code
+  - name: find_code
+    steps:
+      - tool: search_code
+`))
+	require.NoError(t, err)
+	g := &Gateway{virtualTools: virtualTools}
+
+	handler := func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	}
+	registrations := g.virtualToolRegistrations([]ToolRegistration{
+		{ServerName: "github-official", Tool: &mcp.Tool{Name: "get_issue", InputSchema: &jsonschema.Schema{Type: "object"}}, Handler: handler},
+		{ServerName: "github-official", Tool: &mcp.Tool{Name: "search_code", InputSchema: &jsonschema.Schema{Type: "object"}}, Handler: handler},
+	})
+
+	// The server's get_issue isn't replaced by the virtual tool.
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "find_code", registrations[0].Tool.Name)
+}
+
+func TestVirtualToolStepsOutsideOfProfile(t *testing.T) {
+	ctx := t.Context()
+
+	virtualTools, err := virtualtools.Parse([]byte(`
+tools:
+  - name: issue_and_file
+    steps:
+      - tool: get_issue
+      - tool: read_file
+`))
+	require.NoError(t, err)
+	clientProfiles, err := profiles.Parse([]byte(`
+profiles:
+  - name: vscode
+    clients: [vscode]
+    servers: [github-official]
+`))
+	require.NoError(t, err)
+	g := &Gateway{virtualTools: virtualTools, profiles: clientProfiles}
+
+	calls := 0
+	handler := func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+	}
+	registrations := g.virtualToolRegistrations([]ToolRegistration{
+		{ServerName: "github-official", Tool: &mcp.Tool{Name: "get_issue", InputSchema: &jsonschema.Schema{Type: "object"}}, Handler: handler},
+		{ServerName: "filesystem", Tool: &mcp.Tool{Name: "read_file", InputSchema: &jsonschema.Schema{Type: "object"}}, Handler: handler},
+	})
+	require.Len(t, registrations, 1)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(registrations[0].Tool, registrations[0].Handler)
+
+	call := func(clientName string) *mcp.CallToolResult {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		defer serverSession.Close()
+		session, err := mcp.NewClient(&mcp.Implementation{Name: clientName}, nil).Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		defer session.Close()
+
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "issue_and_file"})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call("vscode")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "read_file is not part of the vscode profile")
+	assert.Equal(t, 0, calls)
+
+	result = call("claude")
+	assert.False(t, result.IsError)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// workspaceDir is where the roots of the client are mounted in the containers
+// of the servers that need workspace access.
+const workspaceDir = "/workspace"
+
+// workspaceMount is a client root, bind-mounted into a server's container.
+type workspaceMount struct {
+	HostPath      string
+	ContainerPath string
+	Volume        string
+}
+
+// workspaceMounts maps the file:// roots of a client to bind mounts at
+// /workspace/<name of the directory>. Roots that the mount policy rejects are
+// skipped.
+func (cp *clientPool) workspaceMounts(serverName string, roots []*mcp.Root, readOnly bool) []workspaceMount {
+	var mounts []workspaceMount
+	used := map[string]bool{}
+
+	for _, root := range roots {
+		hostPath, ok := rootPath(root.URI)
+		if !ok {
+			continue
+		}
+
+		name := filepath.Base(hostPath)
+		containerPath := path.Join(workspaceDir, name)
+		for i := 2; used[containerPath]; i++ {
+			containerPath = path.Join(workspaceDir, name+"-"+strconv.Itoa(i))
+		}
+
+		volume := hostPath + ":" + containerPath
+		if readOnly {
+			volume += ":ro"
+		}
+		volume, err := cp.checkMount(volume)
+		if err != nil {
+			logf("  - Not mounting root %s into %s: %s", root.URI, serverName, err)
+			continue
+		}
+
+		used[containerPath] = true
+		mounts = append(mounts, workspaceMount{
+			HostPath:      hostPath,
+			ContainerPath: containerPath,
+			Volume:        volume,
+		})
+	}
+
+	return mounts
+}
+
+// containerRoots rewrites the roots of a client to the paths they are mounted
+// at in a container. Roots that are not mounted are dropped, since the server
+// can't see them.
+func containerRoots(mounts []workspaceMount, roots []*mcp.Root) []*mcp.Root {
+	var rewritten []*mcp.Root
+
+	for _, root := range roots {
+		hostPath, ok := rootPath(root.URI)
+		if !ok {
+			continue
+		}
+
+		for _, mount := range mounts {
+			if mount.HostPath == hostPath {
+				rewritten = append(rewritten, &mcp.Root{
+					Name: root.Name,
+					URI:  (&url.URL{Scheme: "file", Path: mount.ContainerPath}).String(),
+				})
+				break
+			}
+		}
+	}
+
+	return rewritten
+}
+
+// addedRoots are the file:// roots that were not there when the container
+// started. Its mounts can't change anymore, so those roots are dropped.
+func addedRoots(initial []*mcp.Root, roots []*mcp.Root) []*mcp.Root {
+	var added []*mcp.Root
+
+	for _, root := range roots {
+		hostPath, ok := rootPath(root.URI)
+		if !ok {
+			continue
+		}
+
+		if !slices.ContainsFunc(initial, func(initialRoot *mcp.Root) bool {
+			initialPath, ok := rootPath(initialRoot.URI)
+			return ok && initialPath == hostPath
+		}) {
+			added = append(added, root)
+		}
+	}
+
+	return added
+}
+
+// rootPath is the host path of a file:// root.
+func rootPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+
+	p := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/Users/... => C:\Users\...
+		p = strings.TrimPrefix(p, "/")
+	}
+
+	return filepath.Clean(filepath.FromSlash(p)), true
+}
+
+// sessionRoots are the roots of the client behind a session, if it shared them.
+func (cp *clientPool) sessionRoots(config *clientConfig) []*mcp.Root {
+	if cp.gateway == nil || config == nil || config.serverSession == nil {
+		return nil
+	}
+
+	cache := cp.gateway.GetSessionCache(config.serverSession)
+	if cache == nil {
+		return nil
+	}
+	return cache.Roots
+}
@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/mounts"
+)
+
+func TestWorkspaceMounts(t *testing.T) {
+	mountPolicy, err := mounts.Parse([]byte(`deny: [/home/user/secrets]`))
+	require.NoError(t, err)
+	cp := &clientPool{mountPolicy: mountPolicy}
+
+	roots := []*mcp.Root{
+		{Name: "app", URI: "file:///home/user/projects/app"},
+		{Name: "other app", URI: "file:///home/user/other/app"},
+		{Name: "secrets", URI: "file:///home/user/secrets"},
+		{Name: "remote", URI: "https://example.com/repo"},
+	}
+
+	workspace := cp.workspaceMounts("fs", roots, false)
+	assert.Equal(t, []workspaceMount{
+		{HostPath: "/home/user/projects/app", ContainerPath: "/workspace/app", Volume: "/home/user/projects/app:/workspace/app"},
+		{HostPath: "/home/user/other/app", ContainerPath: "/workspace/app-2", Volume: "/home/user/other/app:/workspace/app-2"},
+	}, workspace)
+
+	assert.Equal(t, []*mcp.Root{
+		{Name: "app", URI: "file:///workspace/app"},
+		{Name: "other app", URI: "file:///workspace/app-2"},
+	}, containerRoots(workspace, roots))
+}
+
+func TestWorkspaceMountsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	cp := &clientPool{mountPolicy: mounts.DefaultPolicy()}
+
+	// The home directory contains ~/.ssh, which the default policy denies.
+	workspace := cp.workspaceMounts("fs", []*mcp.Root{{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(home)}).String()}}, false)
+
+	assert.Empty(t, workspace)
+}
+
+func TestWorkspaceMountsReadOnly(t *testing.T) {
+	cp := &clientPool{}
+
+	workspace := cp.workspaceMounts("fs", []*mcp.Root{{URI: "file:///home/user/projects/app"}}, true)
+
+	require.Len(t, workspace, 1)
+	assert.Equal(t, "/home/user/projects/app:/workspace/app:ro", workspace[0].Volume)
+}
+
+func TestServerRoots(t *testing.T) {
+	roots := []*mcp.Root{{URI: "file:///home/user/projects/app"}}
+
+	getter := &clientGetter{serverConfig: &catalog.ServerConfig{Name: "fs"}}
+	assert.Equal(t, roots, getter.serverRoots(roots))
+
+	getter = &clientGetter{
+		serverConfig: &catalog.ServerConfig{Name: "fs", Spec: catalog.Server{Workspace: true}},
+		workspace:    []workspaceMount{{HostPath: "/home/user/projects/app", ContainerPath: "/workspace/app"}},
+	}
+	assert.Equal(t, []*mcp.Root{{URI: "file:///workspace/app"}}, getter.serverRoots(roots))
+}
+
+func TestAddedRoots(t *testing.T) {
+	initial := []*mcp.Root{{URI: "file:///home/user/projects/app"}}
+	roots := []*mcp.Root{
+		{URI: "file:///home/user/projects/app/"},
+		{URI: "file:///home/user/projects/other"},
+		{URI: "https://example.com/repo"},
+	}
+
+	assert.Equal(t, []*mcp.Root{{URI: "file:///home/user/projects/other"}}, addedRoots(initial, roots))
+}
@@ -0,0 +1,110 @@
+package hardening
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+const (
+	// PidsLimit is the maximum number of processes in a server's container.
+	PidsLimit = 512
+
+	// NonRootUser is the user (nobody) that servers opting in run as when
+	// their image would run them as root.
+	NonRootUser = "65534:65534"
+
+	tmpfsOptions = "rw,nosuid,nodev,size=64m"
+)
+
+// Profile is the hardened security profile of an MCP server's container.
+type Profile struct {
+	NoNewPrivileges bool              `json:"noNewPrivileges"`
+	ReadOnlyRootFS  bool              `json:"readOnlyRootfs"`
+	Tmpfs           map[string]string `json:"tmpfs,omitempty"`
+	CapDrop         []string          `json:"capDrop,omitempty"`
+	CapAdd          []string          `json:"capAdd,omitempty"`
+	PidsLimit       int64             `json:"pidsLimit,omitempty"`
+	User            string            `json:"user,omitempty"`
+	Seccomp         string            `json:"seccomp,omitempty"`
+}
+
+// New computes the profile of a server, given the settings it opts out of,
+// the user its image runs as and an optional path to a custom seccomp profile.
+func New(security catalog.Security, imageUser string, seccompProfile string) Profile {
+	profile := Profile{
+		NoNewPrivileges: true,
+	}
+
+	if !security.WritableRootFS {
+		profile.ReadOnlyRootFS = true
+		profile.Tmpfs = map[string]string{"/tmp": tmpfsOptions}
+	}
+	if !security.KeepCapabilities {
+		profile.CapDrop = []string{"ALL"}
+		profile.CapAdd = security.CapAdd
+	}
+	if !security.NoPidsLimit {
+		profile.PidsLimit = PidsLimit
+	}
+	if security.RunAsNonRoot && IsRoot(imageUser) {
+		profile.User = NonRootUser
+	}
+	if !security.DefaultSeccomp {
+		profile.Seccomp = seccompProfile
+	}
+
+	return profile
+}
+
+// IsRoot tells whether a container configured with this user runs as root.
+func IsRoot(user string) bool {
+	name, _, _ := strings.Cut(user, ":")
+	return name == "" || name == "root" || name == "0"
+}
+
+// Apply applies the profile to a container's configuration. The user is only
+// set if the configuration doesn't already have one.
+func (p Profile) Apply(config *container.Config, hostConfig *container.HostConfig) error {
+	if p.NoNewPrivileges && !slices.Contains(hostConfig.SecurityOpt, "no-new-privileges") {
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, "no-new-privileges")
+	}
+	if p.Seccomp != "" {
+		// Unlike the docker CLI, the Engine API expects the content of the profile.
+		buf, err := os.ReadFile(p.Seccomp)
+		if err != nil {
+			return fmt.Errorf("reading seccomp profile: %w", err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, buf); err != nil {
+			return fmt.Errorf("invalid seccomp profile %s: %w", p.Seccomp, err)
+		}
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, "seccomp="+compact.String())
+	}
+
+	hostConfig.ReadonlyRootfs = p.ReadOnlyRootFS
+	for path, options := range p.Tmpfs {
+		if hostConfig.Tmpfs == nil {
+			hostConfig.Tmpfs = map[string]string{}
+		}
+		hostConfig.Tmpfs[path] = options
+	}
+	hostConfig.CapDrop = append(hostConfig.CapDrop, p.CapDrop...)
+	hostConfig.CapAdd = append(hostConfig.CapAdd, p.CapAdd...)
+	if p.PidsLimit > 0 {
+		pidsLimit := p.PidsLimit
+		hostConfig.PidsLimit = &pidsLimit
+	}
+	if config.User == "" {
+		config.User = p.User
+	}
+
+	return nil
+}
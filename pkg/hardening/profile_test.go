@@ -0,0 +1,67 @@
+package hardening
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestIsRoot(t *testing.T) {
+	assert.True(t, IsRoot(""))
+	assert.True(t, IsRoot("root"))
+	assert.True(t, IsRoot("0"))
+	assert.True(t, IsRoot("0:1000"))
+	assert.False(t, IsRoot("node"))
+	assert.False(t, IsRoot("1000:1000"))
+}
+
+func TestNewKeepsNonRootImageUser(t *testing.T) {
+	profile := New(catalog.Security{RunAsNonRoot: true}, "node", "")
+
+	assert.Empty(t, profile.User)
+}
+
+func TestNewRunAsNonRoot(t *testing.T) {
+	assert.Empty(t, New(catalog.Security{}, "root", "").User)
+	assert.Equal(t, NonRootUser, New(catalog.Security{RunAsNonRoot: true}, "root", "").User)
+}
+
+func TestNewOptOut(t *testing.T) {
+	profile := New(catalog.Security{
+		KeepCapabilities: true,
+		CapAdd:           []string{"NET_ADMIN"},
+		DefaultSeccomp:   true,
+	}, "", "/seccomp.json")
+
+	assert.Empty(t, profile.CapDrop)
+	assert.Empty(t, profile.CapAdd)
+	assert.Empty(t, profile.Seccomp)
+	assert.True(t, profile.ReadOnlyRootFS)
+}
+
+func TestApplySeccomp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seccomp.json")
+	require.NoError(t, os.WriteFile(path, []byte("{\n  \"defaultAction\": \"SCMP_ACT_ERRNO\"\n}\n"), 0o644))
+
+	var config container.Config
+	hostConfig := container.HostConfig{SecurityOpt: []string{"no-new-privileges"}}
+	err := New(catalog.Security{}, "", path).Apply(&config, &hostConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"no-new-privileges", `seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`}, hostConfig.SecurityOpt)
+	assert.Empty(t, config.User)
+}
+
+func TestApplyMissingSeccomp(t *testing.T) {
+	var config container.Config
+	var hostConfig container.HostConfig
+	err := New(catalog.Security{}, "", filepath.Join(t.TempDir(), "missing.json")).Apply(&config, &hostConfig)
+
+	require.ErrorContains(t, err, "reading seccomp profile")
+}
@@ -11,9 +11,11 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/google/shlex"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/logs"
 )
 
@@ -51,6 +53,9 @@ type Interceptor struct {
 	When     string
 	Type     string
 	Argument string
+
+	// Docker runs the containers of docker interceptors.
+	Docker docker.Client
 }
 
 // --interceptor=before:exec:/bin/path
@@ -165,19 +170,33 @@ func (i *Interceptor) runExec(ctx context.Context, message []byte) ([]byte, erro
 func (i *Interceptor) runDocker(ctx context.Context, message []byte) ([]byte, error) {
 	image, rest, _ := strings.Cut(i.Argument, " ")
 
-	args := []string{"run", "--rm", "--init", image}
+	if i.Docker == nil {
+		return nil, fmt.Errorf("no docker client to run interceptor image %s", image)
+	}
+
+	useInit := true
+	spec := docker.ContainerSpec{
+		Config: container.Config{
+			Image: image,
+		},
+		HostConfig: container.HostConfig{
+			AutoRemove: true,
+			Init:       &useInit,
+		},
+	}
 	if len(rest) > 0 {
-		moreArgs, err := shlex.Split(rest)
+		args, err := shlex.Split(rest)
 		if err != nil {
 			return nil, fmt.Errorf("parsing docker arguments: %w", err)
 		}
-		args = append(args, moreArgs...)
+		spec.Config.Cmd = args
 	}
 
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Stdin = bytes.NewBuffer(message)
-	cmd.Stderr = logs.NewPrefixer(os.Stderr, "  - ")
-	return cmd.Output()
+	var out bytes.Buffer
+	if err := docker.RunContainer(ctx, i.Docker, spec, bytes.NewBuffer(message), &out, logs.NewPrefixer(os.Stderr, "  - ")); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
 }
 
 func (i *Interceptor) runHTTP(ctx context.Context, message []byte) ([]byte, error) {
@@ -0,0 +1,414 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	DirectionSend    = "send"    // From the gateway to the MCP server
+	DirectionReceive = "receive" // From the MCP server to the gateway
+)
+
+// CassetteEntry is one JSON-RPC message captured on the wire, with the time
+// elapsed since the connection was opened.
+type CassetteEntry struct {
+	// Connection identifies the connection the message was exchanged on.
+	// Every connection numbers its requests from the start, so ids are only
+	// unique within a connection.
+	Connection string          `json:"connection,omitempty"`
+	OffsetMs   int64           `json:"offsetMs"`
+	Direction  string          `json:"direction"`
+	Message    json.RawMessage `json:"message"`
+}
+
+// ReadCassette reads a cassette recorded with NewRecordingTransport.
+func ReadCassette(path string) ([]CassetteEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []CassetteEntry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry CassetteEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// NewRecordingTransport wraps a transport and records every message exchanged
+// over its connections into a cassette file. Successive and concurrent
+// connections are appended to the same cassette, each with its own id.
+func NewRecordingTransport(transport mcp.Transport, path string) mcp.Transport {
+	return &recordingTransport{
+		transport: transport,
+		path:      path,
+	}
+}
+
+type recordingTransport struct {
+	transport mcp.Transport
+	path      string
+}
+
+func (t *recordingTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(t.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening cassette %s: %w", t.path, err)
+	}
+
+	conn, err := t.transport.Connect(ctx)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	start := time.Now()
+	return &recordingConnection{
+		conn:  conn,
+		file:  file,
+		id:    fmt.Sprintf("%x-%d", start.UnixNano(), recordedConnections.Add(1)),
+		start: start,
+	}, nil
+}
+
+// recordedConnections numbers the connections recorded by this process.
+var recordedConnections atomic.Int64
+
+type recordingConnection struct {
+	conn      mcp.Connection
+	id        string
+	start     time.Time
+	mu        sync.Mutex
+	file      *os.File
+	closeOnce sync.Once
+}
+
+func (c *recordingConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	msg, err := c.conn.Read(ctx)
+	if err == nil {
+		c.record(DirectionReceive, msg)
+	}
+	return msg, err
+}
+
+func (c *recordingConnection) Write(ctx context.Context, msg jsonrpc.Message) error {
+	c.record(DirectionSend, msg)
+	return c.conn.Write(ctx, msg)
+}
+
+func (c *recordingConnection) Close() error {
+	err := c.conn.Close()
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_ = c.file.Close()
+	})
+	return err
+}
+
+func (c *recordingConnection) SessionID() string {
+	return c.conn.SessionID()
+}
+
+// record never fails the exchange: a broken cassette shouldn't break the session.
+func (c *recordingConnection) record(direction string, msg jsonrpc.Message) {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return
+	}
+
+	line, err := json.Marshal(CassetteEntry{
+		Connection: c.id,
+		OffsetMs:   time.Since(c.start).Milliseconds(),
+		Direction:  direction,
+		Message:    data,
+	})
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = c.file.Write(append(line, '\n'))
+}
+
+// ReplayTransport plays a cassette back in place of a real MCP server.
+//
+// Each request sent by the client is matched with an unused recorded request
+// with the same method, preferably with the same params, and gets the recorded
+// response with its id rewritten. Once every matching request was used, an
+// identical request gets the same response again. Notifications the server
+// sent while that request was in flight are replayed before the response.
+// Requests from the server to the client (roots, elicitation...) are not
+// replayed.
+type ReplayTransport struct {
+	Entries []CassetteEntry
+	// Realtime reproduces the recorded latency of each response.
+	Realtime bool
+}
+
+func NewReplayTransport(path string) (*ReplayTransport, error) {
+	entries, err := ReadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayTransport{Entries: entries}, nil
+}
+
+type recordedExchange struct {
+	connection    string
+	method        string
+	params        json.RawMessage
+	offsetMs      int64
+	notifications []jsonrpc.Message
+	response      *jsonrpc.Response
+	responseMs    int64
+	followUps     []jsonrpc.Message
+	used          bool
+}
+
+func (t *ReplayTransport) Connect(context.Context) (mcp.Connection, error) {
+	var (
+		exchanges []*recordedExchange
+		inFlight  = map[string]*recordedExchange{}
+	)
+
+	for _, entry := range t.Entries {
+		msg, err := jsonrpc.DecodeMessage(entry.Message)
+		if err != nil {
+			return nil, fmt.Errorf("decoding recorded message: %w", err)
+		}
+
+		switch msg := msg.(type) {
+		case *jsonrpc.Request:
+			switch {
+			case entry.Direction == DirectionSend && msg.ID.IsValid():
+				exchange := &recordedExchange{
+					connection: entry.Connection,
+					method:     msg.Method,
+					params:     msg.Params,
+					offsetMs:   entry.OffsetMs,
+				}
+				exchanges = append(exchanges, exchange)
+				inFlight[entry.Connection+"/"+idKey(msg.ID)] = exchange
+			case entry.Direction == DirectionReceive && !msg.ID.IsValid():
+				if exchange := latestExchange(exchanges, entry.Connection, true); exchange != nil {
+					exchange.notifications = append(exchange.notifications, msg)
+				} else if exchange := latestExchange(exchanges, entry.Connection, false); exchange != nil {
+					exchange.followUps = append(exchange.followUps, msg)
+				}
+			}
+		case *jsonrpc.Response:
+			if entry.Direction != DirectionReceive {
+				continue
+			}
+			key := entry.Connection + "/" + idKey(msg.ID)
+			if exchange, found := inFlight[key]; found {
+				exchange.response = msg
+				exchange.responseMs = entry.OffsetMs
+				delete(inFlight, key)
+			}
+		}
+	}
+
+	return &replayConnection{
+		exchanges: exchanges,
+		realtime:  t.Realtime,
+		incoming:  make(chan jsonrpc.Message, len(t.Entries)+1),
+		closed:    make(chan struct{}),
+	}, nil
+}
+
+// latestExchange is the last exchange recorded on a connection, optionally
+// only among those still waiting for their response.
+func latestExchange(exchanges []*recordedExchange, connection string, inFlight bool) *recordedExchange {
+	for i := len(exchanges) - 1; i >= 0; i-- {
+		exchange := exchanges[i]
+		if exchange.connection == connection && (!inFlight || exchange.response == nil) {
+			return exchange
+		}
+	}
+	return nil
+}
+
+func idKey(id jsonrpc.ID) string {
+	return fmt.Sprintf("%T:%v", id.Raw(), id.Raw())
+}
+
+type replayConnection struct {
+	mu        sync.Mutex
+	exchanges []*recordedExchange
+	realtime  bool
+	incoming  chan jsonrpc.Message
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *replayConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg := <-c.incoming:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, io.EOF
+	}
+}
+
+func (c *replayConnection) Write(_ context.Context, msg jsonrpc.Message) error {
+	req, ok := msg.(*jsonrpc.Request)
+	if !ok || !req.ID.IsValid() {
+		// Notifications and responses to the server's requests have nothing to replay.
+		return nil
+	}
+
+	exchange := c.match(req)
+	if exchange == nil {
+		response, err := errorResponse(req.ID, fmt.Sprintf("no recorded response for %s", req.Method))
+		if err != nil {
+			return err
+		}
+		c.deliver(response)
+		return nil
+	}
+
+	replay := func() {
+		for _, notification := range exchange.notifications {
+			c.deliver(notification)
+		}
+		c.deliver(&jsonrpc.Response{
+			ID:     req.ID,
+			Result: exchange.response.Result,
+			Error:  exchange.response.Error,
+		})
+		for _, followUp := range exchange.followUps {
+			c.deliver(followUp)
+		}
+	}
+
+	if c.realtime {
+		delay := time.Duration(exchange.responseMs-exchange.offsetMs) * time.Millisecond
+		go func() {
+			select {
+			case <-time.After(delay):
+				replay()
+			case <-c.closed:
+			}
+		}()
+	} else {
+		replay()
+	}
+
+	return nil
+}
+
+func (c *replayConnection) match(req *jsonrpc.Request) *recordedExchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var candidate *recordedExchange
+	for _, exchange := range c.exchanges {
+		if exchange.used || exchange.response == nil || exchange.method != req.Method {
+			continue
+		}
+		if sameJSON(exchange.params, req.Params) {
+			candidate = exchange
+			break
+		}
+		if candidate == nil {
+			candidate = exchange
+		}
+	}
+
+	if candidate != nil {
+		candidate.used = true
+		return candidate
+	}
+
+	// Identical requests can be replayed more than once.
+	for _, exchange := range c.exchanges {
+		if exchange.response != nil && exchange.method == req.Method && sameJSON(exchange.params, req.Params) {
+			return exchange
+		}
+	}
+	return nil
+}
+
+func (c *replayConnection) deliver(msg jsonrpc.Message) {
+	select {
+	case c.incoming <- msg:
+	case <-c.closed:
+	}
+}
+
+func (c *replayConnection) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func (c *replayConnection) SessionID() string {
+	return ""
+}
+
+func sameJSON(left, right json.RawMessage) bool {
+	var l, r any
+	if err := json.Unmarshal(left, &l); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(right, &r); err != nil {
+		return false
+	}
+
+	lBuf, _ := json.Marshal(l)
+	rBuf, _ := json.Marshal(r)
+	return bytes.Equal(lBuf, rBuf)
+}
+
+func errorResponse(id jsonrpc.ID, message string) (jsonrpc.Message, error) {
+	buf, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id.Raw(),
+		"error": map[string]any{
+			"code":    -32601,
+			"message": message,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonrpc.DecodeMessage(buf)
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := t.Context()
+	cassette := filepath.Join(t.TempDir(), "echo.jsonl")
+
+	// Record a session with a real, in memory, MCP server.
+	server := mcp.NewServer(&mcp.Implementation{Name: "echo", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{
+		Name:        "echo",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(arguments)}},
+		}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, NewRecordingTransport(clientTransport, cassette), nil)
+	require.NoError(t, err)
+
+	recorded := callEcho(t, session, "first")
+	require.NoError(t, session.Close())
+
+	entries, err := ReadCassette(cassette)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, DirectionSend, entries[0].Direction)
+
+	// Replay it without the server.
+	replay := NewReplayClient("echo", cassette)
+	require.NoError(t, replay.Initialize(ctx, nil, false, nil, nil, nil))
+	defer replay.Session().Close()
+
+	tools, err := replay.Session().ListTools(ctx, &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	assert.Equal(t, "echo", tools.Tools[0].Name)
+
+	assert.Equal(t, recorded, callEcho(t, replay.Session(), "first"))
+
+	// Requests that were not recorded fail.
+	_, err = replay.Session().CallTool(ctx, &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "second"}})
+	require.Error(t, err)
+}
+
+func TestReplayConcurrentConnections(t *testing.T) {
+	// Two connections to the same server, recorded in the same cassette, with
+	// requests that have the same id and responses in a different order.
+	call := func(message string) json.RawMessage {
+		return json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"message":"` + message + `"}}}`)
+	}
+	result := func(message string) json.RawMessage {
+		return json.RawMessage(`{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"` + message + `"}]}}`)
+	}
+	transport := &ReplayTransport{Entries: []CassetteEntry{
+		{Connection: "a", Direction: DirectionSend, Message: call("first")},
+		{Connection: "b", Direction: DirectionSend, Message: call("second")},
+		{Connection: "b", Direction: DirectionReceive, Message: result("second")},
+		{Connection: "a", Direction: DirectionReceive, Message: result("first")},
+	}}
+
+	conn, err := transport.Connect(t.Context())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, message := range []string{"first", "second"} {
+		request, err := jsonrpc.DecodeMessage(call(message))
+		require.NoError(t, err)
+		require.NoError(t, conn.Write(t.Context(), request))
+
+		response, err := conn.Read(t.Context())
+		require.NoError(t, err)
+		require.IsType(t, &jsonrpc.Response{}, response)
+		assert.Nil(t, response.(*jsonrpc.Response).Error)
+		assert.Contains(t, string(response.(*jsonrpc.Response).Result), `"text":"`+message+`"`)
+	}
+}
+
+func callEcho(t *testing.T, session *mcp.ClientSession, message string) string {
+	t.Helper()
+
+	_, err := session.ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": message}})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+
+	return result.Content[0].(*mcp.TextContent).Text
+}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/logs"
+)
+
+type stdioContainerClient struct {
+	name        string
+	docker      docker.Client
+	spec        docker.ContainerSpec
+	client      *mcp.Client
+	session     *mcp.ClientSession
+	roots       []*mcp.Root
+	cassette    string
+	initialized atomic.Bool
+}
+
+// NewStdioContainerClient creates a client for an MCP server that runs in a
+// container and talks over its stdin/stdout. The container is created and
+// attached to through the Docker Engine API.
+func NewStdioContainerClient(name string, docker docker.Client, spec docker.ContainerSpec) Client {
+	return &stdioContainerClient{
+		name:   name,
+		docker: docker,
+		spec:   spec,
+	}
+}
+
+func (c *stdioContainerClient) Initialize(ctx context.Context, params *mcp.InitializeParams, debug bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
+	if c.initialized.Load() {
+		return fmt.Errorf("client already initialized")
+	}
+
+	var stderr io.Writer
+	if debug {
+		stderr = logs.NewPrefixer(os.Stderr, "- "+c.name+": ")
+	}
+
+	var transport mcp.Transport = &containerTransport{
+		ctx:    ctx,
+		docker: c.docker,
+		spec:   c.spec,
+		stderr: stderr,
+	}
+	if c.cassette != "" {
+		transport = NewRecordingTransport(transport, c.cassette)
+	}
+	c.client = mcp.NewClient(clientInfo(params), notifications(ss, server, refresher))
+
+	c.client.AddRoots(c.roots...)
+
+	session, err := c.client.Connect(ctx, transport, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	c.session = session
+	c.initialized.Store(true)
+
+	return nil
+}
+
+func (c *stdioContainerClient) recordTo(path string) {
+	c.cassette = path
+}
+
+func (c *stdioContainerClient) AddRoots(roots []*mcp.Root) {
+	if c.initialized.Load() {
+		c.client.AddRoots(roots...)
+	}
+	c.roots = roots
+}
+
+func (c *stdioContainerClient) Session() *mcp.ClientSession {
+	if !c.initialized.Load() {
+		panic("client not initialize")
+	}
+	return c.session
+}
+
+func (c *stdioContainerClient) GetClient() *mcp.Client {
+	if !c.initialized.Load() {
+		panic("client not initialize")
+	}
+	return c.client
+}
+
+// containerTransport starts a container and exchanges newline-delimited
+// JSON-RPC messages over its attached stdin/stdout, like mcp.CommandTransport
+// does with a process. Like exec.CommandContext, the container is removed once
+// ctx is done.
+type containerTransport struct {
+	ctx    context.Context
+	docker docker.Client
+	spec   docker.ContainerSpec
+	stderr io.Writer
+}
+
+func (t *containerTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	container, err := docker.StartAttached(ctx, t.docker, t.spec, t.stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &containerConnection{
+		container: container,
+		incoming:  make(chan jsonrpc.Message),
+		done:      make(chan struct{}),
+	}
+	stop := context.AfterFunc(t.ctx, func() {
+		_ = t.docker.RemoveContainer(context.Background(), container.ID, true)
+	})
+	conn.stop = stop
+	go conn.readLoop()
+
+	return conn, nil
+}
+
+type containerConnection struct {
+	container *docker.AttachedContainer
+	stop      func() bool
+
+	incoming chan jsonrpc.Message
+	readErr  error
+	done     chan struct{}
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (c *containerConnection) readLoop() {
+	reader := bufio.NewReader(c.container)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			msg, decodeErr := jsonrpc.DecodeMessage(line)
+			if decodeErr != nil {
+				c.readErr = fmt.Errorf("decoding message: %w", decodeErr)
+				close(c.incoming)
+				return
+			}
+
+			select {
+			case c.incoming <- msg:
+			case <-c.done:
+				return
+			}
+		}
+		if err != nil {
+			c.readErr = err
+			close(c.incoming)
+			return
+		}
+	}
+}
+
+func (c *containerConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg, ok := <-c.incoming:
+		if !ok {
+			if c.readErr == nil || errors.Is(c.readErr, io.ErrClosedPipe) {
+				return nil, io.EOF
+			}
+			return nil, c.readErr
+		}
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *containerConnection) Write(_ context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = c.container.Write(append(data, '\n'))
+	return err
+}
+
+func (c *containerConnection) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.stop()
+		c.closeErr = c.container.Close()
+	})
+	return c.closeErr
+}
+
+func (c *containerConnection) SessionID() string {
+	return ""
+}
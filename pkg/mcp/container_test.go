@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func TestStdioContainerClient(t *testing.T) {
+	fake := &fakeDocker{t: t, exit: make(chan container.WaitResponse, 1)}
+	spec := docker.ContainerSpec{
+		Config: container.Config{
+			Image: "mcp/echo",
+			Env:   []string{"KEY=VALUE"},
+		},
+	}
+	cassette := filepath.Join(t.TempDir(), "echo.jsonl")
+
+	client := WithRecording(NewStdioContainerClient("echo", fake, spec), cassette)
+	require.NoError(t, client.Initialize(t.Context(), nil, false, nil, nil, nil))
+
+	assert.Equal(t, `{"message":"hello"}`, callEcho(t, client.Session(), "hello"))
+	require.NoError(t, client.Session().Close())
+
+	assert.Equal(t, "mcp/echo", fake.config.Image)
+	assert.Equal(t, []string{"KEY=VALUE"}, fake.config.Env)
+	assert.True(t, fake.config.AttachStdin)
+
+	entries, err := ReadCassette(cassette)
+	require.NoError(t, err)
+	assert.NotEmpty(t, entries)
+}
+
+// fakeDocker runs an in memory echo MCP server, as if it was running in a
+// container attached to through the Docker Engine API.
+type fakeDocker struct {
+	docker.Client
+	t      *testing.T
+	config container.Config
+	server net.Conn
+	exit   chan container.WaitResponse
+}
+
+func (f *fakeDocker) CreateContainer(_ context.Context, _ string, config container.Config, _ container.HostConfig, _ network.NetworkingConfig) (string, error) {
+	f.config = config
+	return "echo", nil
+}
+
+func (f *fakeDocker) AttachContainer(context.Context, string) (types.HijackedResponse, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(f.t, err)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(f.t, err)
+	f.server = <-accepted
+
+	return types.NewHijackedResponse(conn, ""), nil
+}
+
+func (f *fakeDocker) WaitContainer(context.Context, string, container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	return f.exit, make(chan error)
+}
+
+func (f *fakeDocker) StartCreatedContainer(ctx context.Context, _ string) error {
+	server := mcp.NewServer(&mcp.Implementation{Name: "echo", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{
+		Name:        "echo",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(arguments)}},
+		}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.WithoutCancel(ctx), serverTransport, nil); err != nil {
+		return err
+	}
+	conn, err := clientTransport.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// stdin -> server
+	go func() {
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(f.server)
+		for scanner.Scan() {
+			msg, err := jsonrpc.DecodeMessage(scanner.Bytes())
+			if err != nil || conn.Write(context.Background(), msg) != nil {
+				return
+			}
+		}
+	}()
+
+	// server -> stdout
+	go func() {
+		defer func() {
+			f.server.Close()
+			f.exit <- container.WaitResponse{StatusCode: 0}
+		}()
+
+		stdout := stdcopy.NewStdWriter(f.server, stdcopy.Stdout)
+		for {
+			msg, err := conn.Read(context.Background())
+			if err != nil {
+				return
+			}
+			data, err := jsonrpc.EncodeMessage(msg)
+			if err != nil {
+				return
+			}
+			if _, err := stdout.Write(append(data, '\n')); err != nil {
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (f *fakeDocker) RemoveContainer(context.Context, string, bool) error {
+	return nil
+}
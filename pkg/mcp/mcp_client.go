@@ -15,6 +15,31 @@ type Client interface {
 	AddRoots(roots []*mcp.Root)
 }
 
+// recorder is implemented by the clients that can record their session into a cassette.
+type recorder interface {
+	recordTo(path string)
+}
+
+// WithRecording makes the client record every message it exchanges with the
+// MCP server into a cassette that can be replayed with NewReplayClient.
+func WithRecording(client Client, path string) Client {
+	if r, ok := client.(recorder); ok {
+		r.recordTo(path)
+	}
+	return client
+}
+
+// clientInfo is how the gateway introduces itself to the MCP servers.
+func clientInfo(params *mcp.InitializeParams) *mcp.Implementation {
+	if params != nil && params.ClientInfo != nil {
+		return params.ClientInfo
+	}
+	return &mcp.Implementation{
+		Name:    "docker-mcp-gateway",
+		Version: "1.0.0",
+	}
+}
+
 // CapabilityRefresher interface allows the notification handlers to refresh server capabilities
 type CapabilityRefresher interface {
 	RefreshCapabilities(ctx context.Context, server *mcp.Server, serverSession *mcp.ServerSession) error
@@ -11,6 +11,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
 	"github.com/docker/mcp-gateway/pkg/oauth"
 )
 
@@ -19,6 +20,7 @@ type remoteMCPClient struct {
 	client      *mcp.Client
 	session     *mcp.ClientSession
 	roots       []*mcp.Root
+	cassette    string
 	initialized atomic.Bool
 }
 
@@ -28,7 +30,7 @@ func NewRemoteMCPClient(config *catalog.ServerConfig) Client {
 	}
 }
 
-func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, _ bool, _ *mcp.ServerSession, _ *mcp.Server, _ CapabilityRefresher) error {
+func (c *remoteMCPClient) Initialize(ctx context.Context, params *mcp.InitializeParams, _ bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
 	if c.initialized.Load() {
 		return fmt.Errorf("client already initialized")
 	}
@@ -56,6 +58,9 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 	// Headers
 	headers := map[string]string{}
 	for k, v := range c.config.Spec.Remote.Headers {
+		if strings.Contains(v, "{{") && strings.Contains(v, "}}") {
+			v = fmt.Sprintf("%v", eval.Evaluate(v, c.config.Config))
+		}
 		headers[k] = expandEnv(v, env)
 	}
 
@@ -93,10 +98,11 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 		return fmt.Errorf("unsupported remote transport: %s", transport)
 	}
 
-	c.client = mcp.NewClient(&mcp.Implementation{
-		Name:    "docker-mcp-gateway",
-		Version: "1.0.0",
-	}, nil)
+	if c.cassette != "" {
+		mcpTransport = NewRecordingTransport(mcpTransport, c.cassette)
+	}
+
+	c.client = mcp.NewClient(clientInfo(params), notifications(ss, server, refresher))
 
 	c.client.AddRoots(c.roots...)
 
@@ -114,6 +120,10 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 func (c *remoteMCPClient) Session() *mcp.ClientSession { return c.session }
 func (c *remoteMCPClient) GetClient() *mcp.Client      { return c.client }
 
+func (c *remoteMCPClient) recordTo(path string) {
+	c.cassette = path
+}
+
 func (c *remoteMCPClient) AddRoots(roots []*mcp.Root) {
 	if c.initialized.Load() {
 		c.client.AddRoots(roots...)
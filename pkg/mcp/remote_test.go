@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// remoteServer serves an MCP server over streamable http. Its only tool
+// reports its progress before returning the name of the client that called it.
+func remoteServer(t *testing.T) *catalog.ServerConfig {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "remote", Version: "1.2.3"}, nil)
+	server.AddTool(&mcp.Tool{
+		Name:        "whoami",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if token := req.Params.GetProgressToken(); token != nil {
+			if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{ProgressToken: token, Progress: 1, Message: "working"}); err != nil {
+				return nil, err
+			}
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: req.Session.InitializeParams().ClientInfo.Name}},
+		}, nil
+	})
+
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(httpServer.Close)
+
+	return &catalog.ServerConfig{
+		Name: "remote",
+		Spec: catalog.Server{Remote: catalog.Remote{URL: httpServer.URL, Transport: "streamable-http"}},
+	}
+}
+
+func TestRemoteClientNegotiation(t *testing.T) {
+	client := NewRemoteMCPClient(remoteServer(t))
+	params := &mcp.InitializeParams{ClientInfo: &mcp.Implementation{Name: "my-client", Version: "0.1.0"}}
+	require.NoError(t, client.Initialize(t.Context(), params, false, nil, nil, nil))
+	defer client.Session().Close()
+
+	result := client.Session().InitializeResult()
+	require.NotNil(t, result)
+	assert.NotEmpty(t, result.ProtocolVersion)
+	assert.Equal(t, "remote", result.ServerInfo.Name)
+	assert.Equal(t, "1.2.3", result.ServerInfo.Version)
+
+	// The server sees the gateway's client as its own client.
+	called, err := client.Session().CallTool(t.Context(), &mcp.CallToolParams{Name: "whoami"})
+	require.NoError(t, err)
+	assert.Equal(t, "my-client", called.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestRemoteClientForwardsNotifications(t *testing.T) {
+	ctx := t.Context()
+
+	// The gateway, as seen by a client that asks for progress.
+	progress := make(chan *mcp.ProgressNotificationParams, 1)
+	gateway := mcp.NewServer(&mcp.Implementation{Name: "gateway", Version: "1.0.0"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := gateway.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	downstream := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			progress <- req.Params
+		},
+	})
+	downstreamSession, err := downstream.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer downstreamSession.Close()
+
+	client := NewRemoteMCPClient(remoteServer(t))
+	require.NoError(t, client.Initialize(ctx, nil, false, serverSession, gateway, nil))
+	defer client.Session().Close()
+
+	params := &mcp.CallToolParams{Name: "whoami", Meta: mcp.Meta{}}
+	params.SetProgressToken("token")
+	_, err = client.Session().CallTool(ctx, params)
+	require.NoError(t, err)
+
+	select {
+	case notification := <-progress:
+		assert.Equal(t, "token", notification.ProgressToken)
+		assert.Equal(t, "working", notification.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the progress notification was not forwarded")
+	}
+}
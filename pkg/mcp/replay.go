@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type replayMCPClient struct {
+	name        string
+	path        string
+	client      *mcp.Client
+	session     *mcp.ClientSession
+	roots       []*mcp.Root
+	initialized atomic.Bool
+}
+
+// NewReplayClient creates a client that plays back a cassette instead of talking to a real MCP server.
+func NewReplayClient(name string, path string) Client {
+	return &replayMCPClient{
+		name: name,
+		path: path,
+	}
+}
+
+func (c *replayMCPClient) Initialize(ctx context.Context, params *mcp.InitializeParams, _ bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
+	if c.initialized.Load() {
+		return fmt.Errorf("client already initialized")
+	}
+
+	transport, err := NewReplayTransport(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to read cassette for %s: %w", c.name, err)
+	}
+
+	c.client = mcp.NewClient(clientInfo(params), notifications(ss, server, refresher))
+
+	c.client.AddRoots(c.roots...)
+
+	session, err := c.client.Connect(ctx, transport, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	c.session = session
+	c.initialized.Store(true)
+
+	return nil
+}
+
+func (c *replayMCPClient) AddRoots(roots []*mcp.Root) {
+	if c.initialized.Load() {
+		c.client.AddRoots(roots...)
+	}
+	c.roots = roots
+}
+
+func (c *replayMCPClient) Session() *mcp.ClientSession {
+	if !c.initialized.Load() {
+		panic("client not initialize")
+	}
+	return c.session
+}
+
+func (c *replayMCPClient) GetClient() *mcp.Client {
+	if !c.initialized.Load() {
+		panic("client not initialize")
+	}
+	return c.client
+}
@@ -20,6 +20,7 @@ type stdioMCPClient struct {
 	client      *mcp.Client
 	session     *mcp.ClientSession
 	roots       []*mcp.Root
+	cassette    string
 	initialized atomic.Bool
 }
 
@@ -32,7 +33,7 @@ func NewStdioCmdClient(name string, command string, env []string, args ...string
 	}
 }
 
-func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, debug bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
+func (c *stdioMCPClient) Initialize(ctx context.Context, params *mcp.InitializeParams, debug bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
 	if c.initialized.Load() {
 		return fmt.Errorf("client already initialized")
 	}
@@ -44,11 +45,11 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams
 		cmd.Stderr = logs.NewPrefixer(os.Stderr, "- "+c.name+": ")
 	}
 
-	transport := &mcp.CommandTransport{Command: cmd}
-	c.client = mcp.NewClient(&mcp.Implementation{
-		Name:    "docker-mcp-gateway",
-		Version: "1.0.0",
-	}, notifications(ss, server, refresher))
+	var transport mcp.Transport = &mcp.CommandTransport{Command: cmd}
+	if c.cassette != "" {
+		transport = NewRecordingTransport(transport, c.cassette)
+	}
+	c.client = mcp.NewClient(clientInfo(params), notifications(ss, server, refresher))
 
 	c.client.AddRoots(c.roots...)
 
@@ -63,6 +64,10 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams
 	return nil
 }
 
+func (c *stdioMCPClient) recordTo(path string) {
+	c.cassette = path
+}
+
 func (c *stdioMCPClient) AddRoots(roots []*mcp.Root) {
 	if c.initialized.Load() {
 		c.client.AddRoots(roots...)
@@ -0,0 +1,161 @@
+package mounts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/user"
+)
+
+// Policy restricts which host paths MCP servers can bind-mount. Paths match
+// themselves and everything below them. Symlinks are resolved before matching,
+// both in the policy and in the mounts.
+type Policy struct {
+	// Allow lists the host paths that can be mounted. Empty allows every path
+	// that's not denied.
+	Allow []string `yaml:"allow,omitempty"`
+	// Deny lists the host paths that can never be mounted, nor can their
+	// parents. The usual credential directories are always denied.
+	Deny []string `yaml:"deny,omitempty"`
+	// ReadOnly lists the host paths that are always mounted read-only.
+	ReadOnly []string `yaml:"readOnly,omitempty"`
+}
+
+// defaultDeny keeps the usual credential directories out of the containers.
+// Those paths are denied by every policy.
+var defaultDeny = []string{"~/.ssh", "~/.gnupg", "~/.aws", "~/.azure", "~/.kube", "~/.docker"}
+
+// DefaultPolicy is used when the gateway isn't given a mount policy.
+func DefaultPolicy() *Policy {
+	policy := &Policy{
+		Deny: slices.Clone(defaultDeny),
+	}
+	policy.canonicalize()
+	return policy
+}
+
+func Read(file string) (*Policy, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := Parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mount policy %s: %w", file, err)
+	}
+
+	return policy, nil
+}
+
+func Parse(buf []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(buf, &policy); err != nil {
+		return nil, err
+	}
+
+	for _, path := range slices.Concat(policy.Allow, policy.Deny, policy.ReadOnly) {
+		if !isHostPath(path) {
+			return nil, fmt.Errorf("invalid path %q: must be absolute or start with ~", path)
+		}
+	}
+	policy.Deny = append(policy.Deny, defaultDeny...)
+	policy.canonicalize()
+
+	return &policy, nil
+}
+
+func (p *Policy) canonicalize() {
+	for _, paths := range [][]string{p.Allow, p.Deny, p.ReadOnly} {
+		for i, path := range paths {
+			paths[i] = canonical(path)
+		}
+	}
+}
+
+// Check validates a volume, in the `source:target[:options]` format of
+// `docker run -v`. It returns the volume to mount, with its source
+// canonicalized and made read-only if the policy says so. Named volumes are
+// not checked.
+func (p *Policy) Check(volume string) (string, error) {
+	source, rest := splitSource(volume)
+	if rest == "" || !isHostPath(source) {
+		return volume, nil
+	}
+
+	path := canonical(source)
+	if path == string(filepath.Separator) {
+		return "", fmt.Errorf("mounting the root of the host filesystem is not allowed: %s", volume)
+	}
+	for _, denied := range p.Deny {
+		if isUnder(path, denied) {
+			return "", fmt.Errorf("mounting %s is not allowed: %s is denied by the mount policy", volume, denied)
+		}
+		// Mounting a parent would expose the denied path too.
+		if isUnder(denied, path) {
+			return "", fmt.Errorf("mounting %s is not allowed: it contains %s, which is denied by the mount policy", volume, denied)
+		}
+	}
+	if len(p.Allow) > 0 && !slices.ContainsFunc(p.Allow, func(allowed string) bool { return isUnder(path, allowed) }) {
+		return "", fmt.Errorf("mounting %s is not allowed: %s is not in the paths allowed by the mount policy", volume, path)
+	}
+
+	mount := path + ":" + rest
+	if slices.ContainsFunc(p.ReadOnly, func(readOnly string) bool { return isUnder(path, readOnly) }) && !isReadOnly(rest) {
+		mount += ":ro"
+	}
+
+	return mount, nil
+}
+
+// splitSource splits a volume into its source and the rest. Windows drive
+// letters are part of the source.
+func splitSource(volume string) (string, string) {
+	offset := 0
+	if len(volume) >= 2 && volume[1] == ':' && (volume[0] >= 'A' && volume[0] <= 'Z' || volume[0] >= 'a' && volume[0] <= 'z') {
+		offset = 2
+	}
+
+	i := strings.Index(volume[offset:], ":")
+	if i < 0 {
+		return volume, ""
+	}
+	return volume[:offset+i], volume[offset+i+1:]
+}
+
+func isHostPath(path string) bool {
+	return filepath.IsAbs(path) || strings.HasPrefix(path, "/") || path == "~" || strings.HasPrefix(path, "~/")
+}
+
+func isReadOnly(rest string) bool {
+	_, options, _ := strings.Cut(rest, ":")
+	return slices.Contains(strings.Split(options, ","), "ro")
+}
+
+// canonical expands ~, cleans the path and resolves its symlinks, if it exists.
+func canonical(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := user.HomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	return path
+}
+
+func isUnder(path, parent string) bool {
+	if path == parent || parent == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(parent, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,97 @@
+package mounts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	dir := resolved(t, t.TempDir())
+	projects := filepath.Join(dir, "projects")
+	secrets := filepath.Join(dir, "projects", "secrets")
+	docs := filepath.Join(dir, "docs")
+	for _, path := range []string{projects, secrets, docs} {
+		require.NoError(t, os.MkdirAll(path, 0o755))
+	}
+	require.NoError(t, os.Symlink(secrets, filepath.Join(dir, "link")))
+
+	policy, err := Parse([]byte(`
+allow: [` + projects + `, ` + docs + `]
+deny: [` + secrets + `]
+readOnly: [` + docs + `]
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		volume   string
+		expected string
+		err      string
+	}{
+		{volume: projects + "/app:/work", expected: projects + "/app:/work"},
+		{volume: projects + "/../projects/app:/work:ro", expected: projects + "/app:/work:ro"},
+		{volume: docs + ":/docs", expected: docs + ":/docs:ro"},
+		{volume: docs + ":/docs:ro", expected: docs + ":/docs:ro"},
+		{volume: "data:/data", expected: "data:/data"},
+		{volume: secrets + "/key:/key", err: "denied by the mount policy"},
+		{volume: filepath.Join(dir, "link") + ":/key", err: "denied by the mount policy"},
+		{volume: projects + ":/work", err: "contains " + secrets + ", which is denied by the mount policy"},
+		{volume: dir + "/other:/other", err: "not in the paths allowed by the mount policy"},
+		{volume: "/:/host", err: "root of the host filesystem"},
+	}
+	for _, test := range tests {
+		t.Run(test.volume, func(t *testing.T) {
+			mount, err := policy.Check(test.volume)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, mount)
+		})
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	_, err := DefaultPolicy().Check("~/.ssh:/root/.ssh")
+	require.ErrorContains(t, err, "denied by the mount policy")
+
+	// Mounting the home directory would expose ~/.ssh.
+	_, err = DefaultPolicy().Check("~:/home/user")
+	require.ErrorContains(t, err, "which is denied by the mount policy")
+
+	mount, err := DefaultPolicy().Check("/tmp/work:/work")
+	require.NoError(t, err)
+	assert.Contains(t, mount, ":/work")
+}
+
+func TestCustomPolicyKeepsDefaultDeny(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	policy, err := Parse([]byte(`readOnly: [~/docs]`))
+	require.NoError(t, err)
+
+	_, err = policy.Check("~/.aws:/root/.aws")
+	require.ErrorContains(t, err, "denied by the mount policy")
+}
+
+func TestParseInvalidPath(t *testing.T) {
+	_, err := Parse([]byte(`allow: [relative/path]`))
+	require.ErrorContains(t, err, "must be absolute")
+}
+
+func resolved(t *testing.T, path string) string {
+	t.Helper()
+	path, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	return path
+}
@@ -3,6 +3,7 @@ package oci
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
@@ -94,17 +95,59 @@ type RemoteServer struct {
 // Remote alias for consistency with existing code
 type Remote = RemoteServer
 
-// ToCatalogServer converts an OCI ServerDetail to a catalog.Server
+// Source is what a server converted from the MCP registry runs, when it's
+// published both as a package and as a remote.
+type Source string
+
+const (
+	SourcePackage Source = "package"
+	SourceRemote  Source = "remote"
+)
+
+func ParseSource(value string) (Source, error) {
+	switch Source(value) {
+	case SourcePackage, SourceRemote:
+		return Source(value), nil
+	default:
+		return "", fmt.Errorf("invalid source %q: must be package or remote", value)
+	}
+}
+
+// ToCatalogServer converts an OCI ServerDetail to a catalog.Server. Servers
+// that have both a package and a remote run as the remote.
 func (sd *ServerDetail) ToCatalogServer() catalog.Server {
+	return sd.ToCatalogServerFrom(SourceRemote)
+}
+
+// ToCatalogServerFrom converts an OCI ServerDetail to a catalog.Server that
+// runs from the given source when the server has both a package and a remote,
+// or from whichever it has otherwise. An empty source means the remote.
+func (sd *ServerDetail) ToCatalogServerFrom(source Source) catalog.Server {
 	server := catalog.Server{
 		Description: sd.Description,
 		Name:        sd.Name,
 	}
 
-	// Extract image from the first package if available
-	if len(sd.Packages) > 0 {
-		pkg := sd.Packages[0]
-		server.Image = fmt.Sprintf("%s:%s", pkg.Identifier, pkg.Version)
+	pkg, hasPackage := sd.runnablePackage()
+	remote, hasRemote := sd.supportedRemote()
+	useRemote := hasRemote && (source != SourcePackage || !hasPackage)
+
+	// Run the first package we know how to run: images as is, npm and PyPI
+	// packages in a runner container.
+	if hasPackage && !useRemote {
+		if runner := packageRunner(pkg); runner != "" {
+			version := pkg.Version
+			if version == "" {
+				version = sd.Version
+			}
+			server.Package = &catalog.Package{
+				Runner:  runner,
+				Name:    pkg.Identifier,
+				Version: version,
+			}
+		} else {
+			server.Image = fmt.Sprintf("%s:%s", pkg.Identifier, pkg.Version)
+		}
 
 		// Convert environment variables to secrets, env vars, and config schemas
 		for _, envVar := range pkg.Env {
@@ -155,6 +198,12 @@ func (sd *ServerDetail) ToCatalogServer() catalog.Server {
 
 		// Process runtime arguments
 		for _, arg := range pkg.RuntimeOptions {
+			// For npm and PyPI packages, they are passed to the runner
+			if server.Package != nil {
+				server.Package.RuntimeArgs = append(server.Package.RuntimeArgs, runtimeArgument(&server, arg, CanonicalizeServerName(sd.Name))...)
+				continue
+			}
+
 			// volume arguments have special meaning
 			if arg.Type == "named" && (arg.Name == "-v" || arg.Name == "--mount") {
 				config, volume := createVolume(arg, CanonicalizeServerName(sd.Name))
@@ -169,32 +218,166 @@ func (sd *ServerDetail) ToCatalogServer() catalog.Server {
 		}
 	}
 
-	// Handle remote configuration if available
-	if len(sd.Remotes) > 0 {
-		remote := sd.Remotes[0]
+	// Or connect to the remote
+	if useRemote {
+		serverName := CanonicalizeServerName(sd.Name)
 
-		// Convert KeyValueInput headers to string headers
 		headers := make(map[string]string)
-		headerSecrets := []catalog.Secret{}
 		for _, header := range remote.Headers {
-			value, secrets, _ := getKeyValueInput(header, CanonicalizeServerName(sd.Name), true)
+			value, secrets, config := remoteHeader(header, serverName)
 			headers[header.Name] = value
-			headerSecrets = append(headerSecrets, secrets...)
+			server.Secrets = append(server.Secrets, secrets...)
+			if len(config) > 0 {
+				server.Config = mergeConfig(server.Config, serverName, config)
+			}
 		}
 
 		server.Remote = catalog.Remote{
 			URL:       remote.URL,
-			Transport: remote.TransportType,
+			Transport: remoteTransport(remote.TransportType),
 			Headers:   headers,
 		}
-		if len(headerSecrets) > 0 {
-			server.Secrets = headerSecrets
-		}
 	}
 
 	return server
 }
 
+// runnablePackage is the first package that's an image, or an npm or PyPI package.
+func (sd *ServerDetail) runnablePackage() (Package, bool) {
+	for _, pkg := range sd.Packages {
+		switch pkg.RegistryType {
+		case "", "oci", "docker", "npm", "pypi":
+			return pkg, true
+		}
+	}
+	return Package{}, false
+}
+
+// supportedRemote is the first remote with a transport the gateway supports.
+func (sd *ServerDetail) supportedRemote() (Remote, bool) {
+	for _, remote := range sd.Remotes {
+		if remote.URL != "" && remoteTransport(remote.TransportType) != "" {
+			return remote, true
+		}
+	}
+	return Remote{}, false
+}
+
+// remoteTransport maps the transport of a remote in the MCP registry to the
+// transport of a catalog remote.
+func remoteTransport(transportType string) string {
+	switch transportType {
+	case "streamable-http", "streamable", "http":
+		return "http"
+	case "sse":
+		return "sse"
+	default:
+		return ""
+	}
+}
+
+// remoteHeader converts a header of a remote. Secrets are referenced as ${ENV},
+// expanded from the env of their secret, and the other inputs as
+// {{server.name}} templates, evaluated against the server's config and falling
+// back to the default of the variable, if any.
+func remoteHeader(header KeyValueInput, serverName string) (string, []catalog.Secret, map[string]any) {
+	if len(header.Variables) == 0 {
+		value, secrets, config := getKeyValueInput(header, serverName, true)
+		if len(secrets) > 0 {
+			return value, secrets, nil
+		}
+		return value, nil, config
+	}
+
+	value := header.Value
+	var secrets []catalog.Secret
+	properties := map[string]any{}
+	var required []string
+
+	for varName, variable := range header.Variables {
+		if variable.Secret {
+			secret := catalog.Secret{
+				Name: fmt.Sprintf("%s.%s", serverName, varName),
+				Env:  canonicalizeEnvName(varName),
+			}
+			secrets = append(secrets, secret)
+			value = strings.ReplaceAll(value, "{"+varName+"}", fmt.Sprintf("${%s}", secret.Env))
+			continue
+		}
+
+		prop := map[string]any{
+			"type":        "string",
+			"description": variable.Description,
+		}
+		if variable.DefaultValue != "" {
+			prop["default"] = variable.DefaultValue
+		}
+		if len(variable.Choices) > 0 {
+			prop["enum"] = variable.Choices
+		}
+		properties[varName] = prop
+		if variable.Required {
+			required = append(required, varName)
+		}
+		template := fmt.Sprintf("%s.%s", serverName, varName)
+		if variable.DefaultValue != "" {
+			template += "|or:" + variable.DefaultValue
+		}
+		value = strings.ReplaceAll(value, "{"+varName+"}", "{{"+template+"}}")
+	}
+
+	// Keep the secrets in a stable order
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	if len(properties) == 0 {
+		return value, secrets, nil
+	}
+	config := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		config["required"] = required
+	}
+	return value, secrets, config
+}
+
+// packageRunner is the runner of npm and PyPI packages, or empty for images.
+func packageRunner(pkg Package) string {
+	switch pkg.RegistryType {
+	case "npm":
+		return "npx"
+	case "pypi":
+		return "uvx"
+	default:
+		return ""
+	}
+}
+
+// runtimeArgument converts a runtime argument of an npm or PyPI package to the
+// arguments of its runner, collecting its secrets and config on the way.
+func runtimeArgument(server *catalog.Server, arg Argument, serverName string) []string {
+	value, secrets, configSchema := getInput(arg.InputWithVariables, serverName)
+	server.Secrets = append(server.Secrets, secrets...)
+	if configSchema != nil {
+		server.Config = mergeConfig(server.Config, serverName, configSchema)
+	}
+
+	if arg.Type != "named" {
+		return []string{value}
+	}
+
+	name := arg.Name
+	if !strings.HasPrefix(name, "-") {
+		name = "--" + name
+	}
+	if value == "" {
+		return []string{name}
+	}
+	return []string{name, value}
+}
+
 func getKeyValueInput(kvi KeyValueInput, serverName string, useEnvForm bool) (string, []catalog.Secret, map[string]any) {
 	if len(kvi.Variables) == 0 {
 		if kvi.Secret {
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
 )
 
 func TestServerDetailParsing(t *testing.T) {
@@ -568,3 +570,192 @@ func TestRemoteServerConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestNpmServerConversion(t *testing.T) {
+	serverDetail := readServerDetail(t, "server_npm.json")
+
+	// Convert to catalog server
+	catalogServer := serverDetail.ToCatalogServer()
+
+	// The nuget package is skipped and the npm package runs with npx
+	if catalogServer.Image != "" {
+		t.Errorf("Expected no image, got '%s'", catalogServer.Image)
+	}
+	expectedPackage := &catalog.Package{
+		Runner:      "npx",
+		Name:        "@example/weather-mcp",
+		Version:     "2.1.0",
+		RuntimeArgs: []string{"--node-options", "--max-old-space-size=256"},
+	}
+	if !reflect.DeepEqual(catalogServer.Package, expectedPackage) {
+		t.Errorf("Expected package %+v, got %+v", expectedPackage, catalogServer.Package)
+	}
+
+	expectedCommand := []string{"--units", "{{io_github_example/weather.units}}"}
+	if !reflect.DeepEqual(catalogServer.Command, expectedCommand) {
+		t.Errorf("Expected command %v, got %v", expectedCommand, catalogServer.Command)
+	}
+
+	expectedSecrets := []catalog.Secret{{Name: "io_github_example/weather.WEATHER_API_KEY", Env: "WEATHER_API_KEY"}}
+	if !reflect.DeepEqual(catalogServer.Secrets, expectedSecrets) {
+		t.Errorf("Expected secrets %v, got %v", expectedSecrets, catalogServer.Secrets)
+	}
+
+	expectedEnv := []catalog.Env{{Name: "LOG_LEVEL", Value: "info"}}
+	if !reflect.DeepEqual(catalogServer.Env, expectedEnv) {
+		t.Errorf("Expected env %v, got %v", expectedEnv, catalogServer.Env)
+	}
+
+	if !hasConfigProperty(catalogServer, "units") {
+		t.Errorf("Expected a units config property, got %v", catalogServer.Config)
+	}
+}
+
+func TestPypiServerConversion(t *testing.T) {
+	serverDetail := readServerDetail(t, "server_pypi.json")
+
+	// Convert to catalog server
+	catalogServer := serverDetail.ToCatalogServer()
+
+	// The package has no version, so the server's version is pinned
+	expectedPackage := &catalog.Package{
+		Runner:      "uvx",
+		Name:        "notes-mcp",
+		Version:     "0.4.2",
+		RuntimeArgs: []string{"--python", "3.12"},
+	}
+	if !reflect.DeepEqual(catalogServer.Package, expectedPackage) {
+		t.Errorf("Expected package %+v, got %+v", expectedPackage, catalogServer.Package)
+	}
+
+	expectedCommand := []string{"serve"}
+	if !reflect.DeepEqual(catalogServer.Command, expectedCommand) {
+		t.Errorf("Expected command %v, got %v", expectedCommand, catalogServer.Command)
+	}
+
+	expectedEnv := []catalog.Env{{Name: "NOTES_DIR", Value: "{{io_github_example/notes.NOTES_DIR}}"}}
+	if !reflect.DeepEqual(catalogServer.Env, expectedEnv) {
+		t.Errorf("Expected env %v, got %v", expectedEnv, catalogServer.Env)
+	}
+
+	if !hasConfigProperty(catalogServer, "NOTES_DIR") {
+		t.Errorf("Expected a NOTES_DIR config property, got %v", catalogServer.Config)
+	}
+}
+
+func TestPackageAndRemoteConversion(t *testing.T) {
+	serverDetail := readServerDetail(t, "server_package_and_remote.json")
+
+	// The package runs only when it's preferred
+	catalogServer := serverDetail.ToCatalogServerFrom(SourcePackage)
+	if catalogServer.Package == nil || catalogServer.Package.Name != "@example/tickets-mcp" {
+		t.Errorf("Expected the npm package, got %+v", catalogServer.Package)
+	}
+	if catalogServer.Remote.URL != "" {
+		t.Errorf("Expected no remote, got '%s'", catalogServer.Remote.URL)
+	}
+
+	// The remote is the default, and the websocket remote is skipped
+	catalogServer = serverDetail.ToCatalogServer()
+	if catalogServer.Package != nil {
+		t.Errorf("Expected no package, got %+v", catalogServer.Package)
+	}
+	expectedRemote := catalog.Remote{
+		URL:       "https://mcp.tickets.example.com/mcp",
+		Transport: "http",
+		Headers: map[string]string{
+			"Authorization": "Bearer ${token}",
+			"X-Workspace":   "{{io_github_example/tickets.X-Workspace}}",
+		},
+	}
+	if !reflect.DeepEqual(catalogServer.Remote, expectedRemote) {
+		t.Errorf("Expected remote %+v, got %+v", expectedRemote, catalogServer.Remote)
+	}
+	if !reflect.DeepEqual(serverDetail.ToCatalogServerFrom(SourceRemote), catalogServer) {
+		t.Errorf("Expected the remote to be the default")
+	}
+
+	// Only the secrets of the remote are kept
+	expectedSecrets := []catalog.Secret{{Name: "io_github_example/tickets.token", Env: "token"}}
+	if !reflect.DeepEqual(catalogServer.Secrets, expectedSecrets) {
+		t.Errorf("Expected secrets %v, got %v", expectedSecrets, catalogServer.Secrets)
+	}
+
+	if !hasConfigProperty(catalogServer, "X-Workspace") {
+		t.Errorf("Expected a X-Workspace config property, got %v", catalogServer.Config)
+	}
+}
+
+func TestRemoteOnlyConversionPreferringPackage(t *testing.T) {
+	serverDetail := readServerDetail(t, "server.remote.json")
+
+	catalogServer := serverDetail.ToCatalogServerFrom(SourcePackage)
+
+	if catalogServer.Remote.URL != "http://mcp-fs.anonymous.modelcontextprotocol.io/sse" {
+		t.Errorf("Expected the remote to be used, got '%s'", catalogServer.Remote.URL)
+	}
+	if catalogServer.Remote.Headers["X-API-Key"] != "${X_API_Key}" {
+		t.Errorf("Expected the X-API-Key header to reference its secret, got '%s'", catalogServer.Remote.Headers["X-API-Key"])
+	}
+}
+
+func TestRemoteHeaderDefault(t *testing.T) {
+	header := KeyValueInput{
+		Name: "X-Region",
+		InputWithVariables: InputWithVariables{
+			Input: Input{Value: "{region}/{zone}"},
+			Variables: map[string]Input{
+				"region": {DefaultValue: "us-east-1"},
+				"zone":   {},
+			},
+		},
+	}
+
+	value, _, _ := remoteHeader(header, "example")
+	if value != "{{example.region|or:us-east-1}}/{{example.zone}}" {
+		t.Errorf("Expected the header to fall back to the default region, got '%s'", value)
+	}
+	if evaluated := eval.Evaluate(value, map[string]any{"example": map[string]any{"zone": "a"}}); evaluated != "us-east-1/a" {
+		t.Errorf("Expected 'us-east-1/a', got '%v'", evaluated)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	source, err := ParseSource("remote")
+	if err != nil || source != SourceRemote {
+		t.Errorf("Expected remote, got '%s' (%v)", source, err)
+	}
+
+	if _, err := ParseSource("image"); err == nil {
+		t.Error("Expected an error for an invalid source")
+	}
+}
+
+func readServerDetail(t *testing.T, name string) ServerDetail {
+	t.Helper()
+
+	testDataPath := filepath.Join("..", "..", "test", "testdata", "mcpregistry", name)
+	jsonData, err := os.ReadFile(testDataPath)
+	if err != nil {
+		t.Fatalf("Failed to read test data file %s: %v", testDataPath, err)
+	}
+
+	var serverDetail ServerDetail
+	if err := json.Unmarshal(jsonData, &serverDetail); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	return serverDetail
+}
+
+func hasConfigProperty(server catalog.Server, name string) bool {
+	for _, config := range server.Config {
+		if configMap, ok := config.(map[string]any); ok {
+			if properties, ok := configMap["properties"].(map[string]any); ok {
+				if _, found := properties[name]; found {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package overrides
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/eval"
+)
+
+// AllTools is the tool name of the overrides that apply to every tool of a
+// server. Only its argument rules, on the tools that have those arguments, and
+// its response transform are used.
+const AllTools = "*"
+
+// Overrides rewrite how the tools of MCP servers are exposed to clients,
+// without changing the servers themselves. They are keyed by server name,
+// then by upstream tool name.
+type Overrides struct {
+	Servers map[string]map[string]ToolOverride `yaml:",inline"`
+}
+
+type ToolOverride struct {
+	Name        string                      `yaml:"name,omitempty"`
+	Title       string                      `yaml:"title,omitempty"`
+	Description string                      `yaml:"description,omitempty"`
+	Arguments   map[string]ArgumentOverride `yaml:"arguments,omitempty"`
+	Response    *ResponseTransform          `yaml:"response,omitempty"`
+}
+
+// ArgumentOverride rewrites the description of an argument or removes it from
+// the input schema. A pinned argument is removed from the schema and always
+// gets the same value. An argument with a default gets that value when the
+// client omits it. Values and defaults that are strings can be templated from
+// the server's config, eg. `{{github.owner}}`.
+type ArgumentOverride struct {
+	Description string `yaml:"description,omitempty"`
+	Hidden      bool   `yaml:"hidden,omitempty"`
+	Value       any    `yaml:"value,omitempty"`
+	Default     any    `yaml:"default,omitempty"`
+}
+
+func (a ArgumentOverride) pinned() bool {
+	return a.Value != nil
+}
+
+func Read(file string) (*Overrides, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := Parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing tool overrides %s: %w", file, err)
+	}
+
+	return overrides, nil
+}
+
+func Parse(buf []byte) (*Overrides, error) {
+	var overrides Overrides
+	if err := yaml.Unmarshal(buf, &overrides); err != nil {
+		return nil, err
+	}
+
+	for serverName, tools := range overrides.Servers {
+		names := map[string]string{}
+		for toolName, override := range tools {
+			for name, argument := range override.Arguments {
+				if argument.pinned() && argument.Default != nil {
+					return nil, fmt.Errorf("server %s: argument %s of %s can't have both a value and a default", serverName, name, toolName)
+				}
+			}
+			if override.Response != nil {
+				if err := override.Response.validate(); err != nil {
+					return nil, fmt.Errorf("server %s: response of %s: %w", serverName, toolName, err)
+				}
+			}
+			if toolName == AllTools {
+				if override.Name != "" || override.Title != "" || override.Description != "" {
+					return nil, fmt.Errorf("server %s: only arguments and responses can be overridden for all the tools", serverName)
+				}
+				continue
+			}
+
+			name := override.Name
+			if name == "" {
+				name = toolName
+			}
+			if other, found := names[name]; found {
+				return nil, fmt.Errorf("server %s: tools %s and %s would both be named %s", serverName, other, toolName, name)
+			}
+			names[name] = toolName
+		}
+	}
+
+	return &overrides, nil
+}
+
+// For returns the overrides of a tool, including the argument rules that apply
+// to all the tools of its server, or nil if there's none.
+func (o *Overrides) For(serverName string, tool *mcp.Tool) *ToolOverride {
+	tools := o.Servers[serverName]
+	override, found := tools[tool.Name]
+	allTools, foundAllTools := tools[AllTools]
+	if !found && !foundAllTools {
+		return nil
+	}
+
+	override.Arguments = maps.Clone(override.Arguments)
+	for name, argument := range allTools.Arguments {
+		if _, found := override.Arguments[name]; found || !hasArgument(tool, name) {
+			continue
+		}
+		if override.Arguments == nil {
+			override.Arguments = map[string]ArgumentOverride{}
+		}
+		override.Arguments[name] = argument
+	}
+
+	if override.Response == nil {
+		override.Response = allTools.Response
+	}
+
+	if override.Name == "" && override.Title == "" && override.Description == "" && len(override.Arguments) == 0 && override.Response == nil {
+		return nil
+	}
+	return &override
+}
+
+func hasArgument(tool *mcp.Tool, name string) bool {
+	if tool.InputSchema == nil {
+		return false
+	}
+	_, found := tool.InputSchema.Properties[name]
+	return found
+}
+
+// Apply returns a copy of the tool, as it should be exposed to clients.
+func (o *ToolOverride) Apply(tool *mcp.Tool, config map[string]any) *mcp.Tool {
+	overridden := *tool
+	if o.Name != "" {
+		overridden.Name = o.Name
+	}
+	if o.Title != "" {
+		overridden.Title = o.Title
+	}
+	if o.Description != "" {
+		overridden.Description = o.Description
+	}
+	if o.Response != nil {
+		// The transformed results no longer match the upstream output schema.
+		overridden.OutputSchema = nil
+	}
+
+	if len(o.Arguments) == 0 || tool.InputSchema == nil {
+		return &overridden
+	}
+
+	schema := *tool.InputSchema
+	schema.Properties = maps.Clone(schema.Properties)
+	schema.Required = slices.Clone(schema.Required)
+	for name, argument := range o.Arguments {
+		if argument.Hidden || argument.pinned() {
+			delete(schema.Properties, name)
+			schema.Required = slices.DeleteFunc(schema.Required, func(required string) bool { return required == name })
+			continue
+		}
+
+		property, found := schema.Properties[name]
+		if !found || property == nil {
+			continue
+		}
+
+		if argument.Default != nil {
+			schema.Required = slices.DeleteFunc(schema.Required, func(required string) bool { return required == name })
+		}
+		if argument.Description == "" && argument.Default == nil {
+			continue
+		}
+
+		overridden := *property
+		if argument.Description != "" {
+			overridden.Description = argument.Description
+		}
+		if argument.Default != nil {
+			// A default that can't be evaluated is reported when the tool is called.
+			if value, err := evaluate(argument.Default, config); err == nil {
+				if buf, err := json.Marshal(value); err == nil {
+					overridden.Default = buf
+				}
+			}
+		}
+		schema.Properties[name] = &overridden
+	}
+	overridden.InputSchema = &schema
+
+	return &overridden
+}
+
+// TranslateArguments translates the arguments sent by a client into the arguments
+// expected by the upstream tool: hidden arguments are dropped, pinned arguments
+// are set and omitted arguments get their default. It fails when a value or a
+// default is templated from a config key that is not set.
+func (o *ToolOverride) TranslateArguments(arguments map[string]any, config map[string]any) (map[string]any, error) {
+	if len(o.Arguments) == 0 {
+		return arguments, nil
+	}
+
+	translated := maps.Clone(arguments)
+	if translated == nil {
+		translated = map[string]any{}
+	}
+	for name, argument := range o.Arguments {
+		switch {
+		case argument.pinned():
+			value, err := evaluate(argument.Value, config)
+			if err != nil {
+				return nil, fmt.Errorf("argument %s: %w", name, err)
+			}
+			translated[name] = value
+		case argument.Hidden:
+			delete(translated, name)
+		case argument.Default != nil:
+			if _, found := translated[name]; !found {
+				value, err := evaluate(argument.Default, config)
+				if err != nil {
+					return nil, fmt.Errorf("argument %s: %w", name, err)
+				}
+				translated[name] = value
+			}
+		}
+	}
+	return translated, nil
+}
+
+var templateTerm = regexp.MustCompile(`{{(.*?)}}`)
+
+// evaluate expands the templates of string values. Every config key the
+// template uses must be set, unless it has a fallback with `|or:`.
+func evaluate(value any, config map[string]any) (any, error) {
+	s, ok := value.(string)
+	if !ok || !strings.Contains(s, "{{") {
+		return value, nil
+	}
+
+	for _, match := range templateTerm.FindAllStringSubmatch(s, -1) {
+		key, functions, _ := strings.Cut(match[1], "|")
+		key = strings.TrimSpace(key)
+		if !strings.Contains(functions, "or:") && !hasConfig(config, key) {
+			return nil, fmt.Errorf("config %s is not set", key)
+		}
+	}
+
+	return eval.Evaluate(s, config), nil
+}
+
+func hasConfig(config map[string]any, key string) bool {
+	top, rest, found := strings.Cut(key, ".")
+	value, ok := config[strings.TrimSpace(top)]
+	if !ok || value == nil {
+		return false
+	}
+	if !found {
+		return true
+	}
+	child, ok := value.(map[string]any)
+	return ok && hasConfig(child, rest)
+}
@@ -0,0 +1,178 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOverrides = `
+github-official:
+  search_repositories:
+    name: find_repos
+    title: Find repositories
+    description: Search GitHub repositories.
+    arguments:
+      query:
+        description: What to look for.
+      perPage:
+        value: 10
+      page:
+        hidden: true
+`
+
+func searchRepositories() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "search_repositories",
+		Description: "A very long and vague description.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query":   {Type: "string", Description: "Query"},
+				"perPage": {Type: "number"},
+				"page":    {Type: "number"},
+			},
+			Required: []string{"query", "perPage"},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	overrides, err := Parse([]byte(testOverrides))
+	require.NoError(t, err)
+
+	assert.Nil(t, overrides.For("github-official", &mcp.Tool{Name: "get_issue"}))
+	assert.Nil(t, overrides.For("filesystem", searchRepositories()))
+
+	upstream := searchRepositories()
+	tool := overrides.For("github-official", upstream).Apply(upstream, nil)
+
+	assert.Equal(t, "find_repos", tool.Name)
+	assert.Equal(t, "Find repositories", tool.Title)
+	assert.Equal(t, "Search GitHub repositories.", tool.Description)
+	assert.Equal(t, []string{"query"}, tool.InputSchema.Required)
+	require.Len(t, tool.InputSchema.Properties, 1)
+	assert.Equal(t, "What to look for.", tool.InputSchema.Properties["query"].Description)
+
+	// The upstream tool is left untouched.
+	assert.Equal(t, searchRepositories(), upstream)
+}
+
+func TestTranslateArguments(t *testing.T) {
+	overrides, err := Parse([]byte(testOverrides))
+	require.NoError(t, err)
+
+	override := overrides.For("github-official", searchRepositories())
+	assert.Equal(t, map[string]any{"query": "mcp", "perPage": 10}, translate(t, override, map[string]any{"query": "mcp", "page": 3, "perPage": 100}, nil))
+	assert.Equal(t, map[string]any{"perPage": 10}, translate(t, override, nil, nil))
+}
+
+func TestArgumentRules(t *testing.T) {
+	overrides, err := Parse([]byte(`
+github-official:
+  "*":
+    arguments:
+      owner:
+        value: "{{github-official.owner}}"
+      repo:
+        default: mcp-gateway
+  list_repositories:
+    arguments:
+      owner:
+        default: docker
+`))
+	require.NoError(t, err)
+	config := map[string]any{"github-official": map[string]any{"owner": "myorg"}}
+
+	getIssue := &mcp.Tool{
+		Name: "get_issue",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner":  {Type: "string"},
+				"repo":   {Type: "string"},
+				"number": {Type: "number"},
+			},
+			Required: []string{"owner", "repo", "number"},
+		},
+	}
+	override := overrides.For("github-official", getIssue)
+	require.NotNil(t, override)
+
+	tool := override.Apply(getIssue, config)
+	assert.Equal(t, []string{"number"}, tool.InputSchema.Required)
+	assert.NotContains(t, tool.InputSchema.Properties, "owner")
+	assert.JSONEq(t, `"mcp-gateway"`, string(tool.InputSchema.Properties["repo"].Default))
+
+	assert.Equal(t, map[string]any{"owner": "myorg", "repo": "mcp-gateway", "number": 1}, translate(t, override, map[string]any{"owner": "other", "number": 1}, config))
+	assert.Equal(t, map[string]any{"owner": "myorg", "repo": "other", "number": 1}, translate(t, override, map[string]any{"repo": "other", "number": 1}, config))
+
+	// Rules for all the tools only apply to tools with those arguments.
+	search := &mcp.Tool{Name: "search_code", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"q": {Type: "string"}}}}
+	assert.Nil(t, overrides.For("github-official", search))
+
+	// Rules for a tool take precedence.
+	listRepositories := &mcp.Tool{Name: "list_repositories", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"owner": {Type: "string"}}}}
+	override = overrides.For("github-official", listRepositories)
+	assert.Equal(t, map[string]any{"owner": "docker"}, translate(t, override, nil, config))
+
+	// Config keys that are not set are errors, not empty values.
+	override = overrides.For("github-official", getIssue)
+	_, err = override.TranslateArguments(map[string]any{"number": 1}, map[string]any{})
+	require.ErrorContains(t, err, "config github-official.owner is not set")
+}
+
+func TestTemplateFallback(t *testing.T) {
+	overrides, err := Parse([]byte(`
+github-official:
+  "*":
+    arguments:
+      owner:
+        value: "{{github-official.owner|or:docker}}"
+`))
+	require.NoError(t, err)
+
+	override := overrides.For("github-official", &mcp.Tool{Name: "get_issue", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"owner": {Type: "string"}}}})
+	assert.Equal(t, map[string]any{"owner": "docker"}, translate(t, override, nil, nil))
+}
+
+func translate(t *testing.T, override *ToolOverride, arguments, config map[string]any) map[string]any {
+	t.Helper()
+
+	translated, err := override.TranslateArguments(arguments, config)
+	require.NoError(t, err)
+	return translated
+}
+
+func TestDuplicateNames(t *testing.T) {
+	_, err := Parse([]byte(`
+github-official:
+  search_repositories:
+    name: search
+  search_code:
+    name: search
+`))
+	require.ErrorContains(t, err, "would both be named search")
+}
+
+func TestInvalidArgumentRules(t *testing.T) {
+	_, err := Parse([]byte(`
+github-official:
+  "*":
+    name: renamed
+`))
+	require.ErrorContains(t, err, "only arguments and responses can be overridden for all the tools")
+
+	_, err = Parse([]byte(`
+github-official:
+  get_issue:
+    arguments:
+      owner:
+        value: docker
+        default: docker
+`))
+	require.ErrorContains(t, err, "can't have both a value and a default")
+}
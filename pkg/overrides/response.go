@@ -0,0 +1,125 @@
+package overrides
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// ResponseTransform reduces the JSON results of a tool before they reach the
+// client. The steps are applied in this order:
+//   - JSONPath projects the result, eg. `$.items[*]`.
+//   - Include keeps only some fields of the result, or of each object when the
+//     result is a list.
+//   - Exclude removes fields at any depth.
+//   - MaxItems truncates lists at any depth.
+type ResponseTransform struct {
+	JSONPath string   `yaml:"jsonpath,omitempty"`
+	Include  []string `yaml:"include,omitempty"`
+	Exclude  []string `yaml:"exclude,omitempty"`
+	MaxItems int      `yaml:"maxItems,omitempty"`
+}
+
+func (r *ResponseTransform) validate() error {
+	if r.JSONPath != "" {
+		if _, err := jsonpath.New(r.JSONPath); err != nil {
+			return fmt.Errorf("invalid jsonpath %q: %w", r.JSONPath, err)
+		}
+	}
+	if r.MaxItems < 0 {
+		return fmt.Errorf("invalid maxItems %d", r.MaxItems)
+	}
+	return nil
+}
+
+// Apply transforms a JSON value, as decoded by encoding/json.
+func (r *ResponseTransform) Apply(value any) (any, error) {
+	if r.JSONPath != "" {
+		projected, err := jsonpath.Get(r.JSONPath, value)
+		if err != nil {
+			return nil, err
+		}
+		value = projected
+	}
+
+	if len(r.Include) > 0 {
+		value = include(value, r.Include)
+	}
+	if len(r.Exclude) > 0 {
+		value = exclude(value, r.Exclude)
+	}
+	if r.MaxItems > 0 {
+		value = truncate(value, r.MaxItems)
+	}
+
+	return value, nil
+}
+
+func include(value any, fields []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		kept := map[string]any{}
+		for _, field := range fields {
+			if fieldValue, found := v[field]; found {
+				kept[field] = fieldValue
+			}
+		}
+		return kept
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			if _, isObject := item.(map[string]any); isObject {
+				items[i] = include(item, fields)
+			} else {
+				items[i] = item
+			}
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func exclude(value any, fields []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		kept := map[string]any{}
+		for field, fieldValue := range v {
+			if !slices.Contains(fields, field) {
+				kept[field] = exclude(fieldValue, fields)
+			}
+		}
+		return kept
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = exclude(item, fields)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func truncate(value any, maxItems int) any {
+	switch v := value.(type) {
+	case map[string]any:
+		truncated := map[string]any{}
+		for field, fieldValue := range v {
+			truncated[field] = truncate(fieldValue, maxItems)
+		}
+		return truncated
+	case []any:
+		if len(v) > maxItems {
+			v = v[:maxItems]
+		}
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = truncate(item, maxItems)
+		}
+		return items
+	default:
+		return value
+	}
+}
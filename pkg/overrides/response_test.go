@@ -0,0 +1,63 @@
+package overrides
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const searchResult = `{
+	"total_count": 3,
+	"items": [
+		{"name": "a", "url": "https://a", "owner": {"login": "x", "_links": {}}, "topics": ["1", "2", "3"]},
+		{"name": "b", "url": "https://b", "owner": {"login": "y", "_links": {}}, "topics": []},
+		{"name": "c", "url": "https://c", "owner": {"login": "z", "_links": {}}, "topics": ["1"]}
+	]
+}`
+
+func applyTransform(t *testing.T, transform ResponseTransform) string {
+	t.Helper()
+
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(searchResult), &value))
+
+	transformed, err := transform.Apply(value)
+	require.NoError(t, err)
+
+	buf, err := json.Marshal(transformed)
+	require.NoError(t, err)
+	return string(buf)
+}
+
+func TestResponseTransform(t *testing.T) {
+	assert.JSONEq(t, `["a", "b", "c"]`, applyTransform(t, ResponseTransform{JSONPath: "$.items[*].name"}))
+	assert.JSONEq(t, `[{"name": "a", "url": "https://a"}, {"name": "b", "url": "https://b"}]`, applyTransform(t, ResponseTransform{
+		JSONPath: "$.items",
+		Include:  []string{"name", "url"},
+		MaxItems: 2,
+	}))
+	assert.JSONEq(t, `{"total_count": 3, "items": [{"name": "a", "owner": {"login": "x"}, "topics": ["1"]}]}`, applyTransform(t, ResponseTransform{
+		Exclude:  []string{"url", "_links"},
+		MaxItems: 1,
+	}))
+}
+
+func TestInvalidResponseTransform(t *testing.T) {
+	_, err := Parse([]byte(`
+github-official:
+  search_repositories:
+    response:
+      jsonpath: "$.items[("
+`))
+	require.ErrorContains(t, err, "invalid jsonpath")
+
+	_, err = Parse([]byte(`
+github-official:
+  "*":
+    response:
+      maxItems: -1
+`))
+	require.ErrorContains(t, err, "invalid maxItems")
+}
@@ -0,0 +1,369 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+)
+
+// Policy is an ordered list of rules. The first rule that matches a call decides
+// whether it's allowed. Calls that match no rule get the default action.
+type Policy struct {
+	Default string `yaml:"default,omitempty"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule matches calls by server, tool, client and arguments.
+// Empty fields match everything. Tools are known by the name clients see,
+// after any override.
+type Rule struct {
+	Name      string               `yaml:"name,omitempty"`
+	Action    string               `yaml:"action"`
+	Server    string               `yaml:"server,omitempty"`
+	Tools     []string             `yaml:"tools,omitempty"`
+	Clients   []string             `yaml:"clients,omitempty"`
+	Arguments map[string]Predicate `yaml:"arguments,omitempty"`
+	Reason    string               `yaml:"reason,omitempty"`
+}
+
+// Predicate constrains the value of an argument. All the conditions that are set
+// must hold.
+//
+// Predicates fail closed. An argument that is missing never satisfies the
+// predicate of an allow rule and always satisfies the predicate of a deny rule,
+// unless the predicate is marked optional. When the argument is a list, an allow rule requires
+// every item to satisfy the predicate while a deny rule only needs one. A value
+// that can't be checked, like a number for a string condition or a relative
+// path for a path condition, never satisfies the predicate of an allow rule and
+// always satisfies the predicate of a deny rule.
+type Predicate struct {
+	Under       string `yaml:"under,omitempty"`
+	NotUnder    string `yaml:"notUnder,omitempty"`
+	Contains    string `yaml:"contains,omitempty"`    // Case insensitive
+	NotContains string `yaml:"notContains,omitempty"` // Case insensitive
+	Matches     string `yaml:"matches,omitempty"`     // Regular expression
+	Equals      any    `yaml:"equals,omitempty"`
+	OneOf       []any  `yaml:"oneOf,omitempty"`
+	// Optional is for arguments that can be left out: a missing argument then
+	// satisfies no predicate, even for a deny rule.
+	Optional bool `yaml:"optional,omitempty"`
+
+	matches *regexp.Regexp
+}
+
+// Call is a tool call, as seen by the policy.
+type Call struct {
+	Server    string         `yaml:"server" json:"server"`
+	Tool      string         `yaml:"tool" json:"tool"`
+	Client    string         `yaml:"client,omitempty" json:"client,omitempty"`
+	Arguments map[string]any `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+}
+
+// Decision is the outcome of evaluating a call against a policy.
+type Decision struct {
+	Allowed bool
+	// Rule is the index of the matching rule, or -1 if the default action was used.
+	Rule        int
+	Explanation string
+}
+
+func Read(file string) (*Policy, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := Parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing policy %s: %w", file, err)
+	}
+
+	return policy, nil
+}
+
+func Parse(buf []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(buf, &policy); err != nil {
+		return nil, err
+	}
+
+	if policy.Default == "" {
+		policy.Default = ActionAllow
+	}
+	if policy.Default != ActionAllow && policy.Default != ActionDeny {
+		return nil, fmt.Errorf("invalid default action %q: must be allow or deny", policy.Default)
+	}
+
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if rule.Action != ActionAllow && rule.Action != ActionDeny {
+			return nil, fmt.Errorf("rule %s: invalid action %q: must be allow or deny", rule.label(i), rule.Action)
+		}
+
+		for _, pattern := range append(append([]string{rule.Server}, rule.Tools...), rule.Clients...) {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %s: invalid pattern %q: %w", rule.label(i), pattern, err)
+			}
+		}
+
+		for name, predicate := range rule.Arguments {
+			for _, dir := range []string{predicate.Under, predicate.NotUnder} {
+				if dir != "" && !isAbs(dir) {
+					return nil, fmt.Errorf("rule %s: path %q for argument %q must be absolute", rule.label(i), dir, name)
+				}
+			}
+			if predicate.Matches != "" {
+				re, err := regexp.Compile(predicate.Matches)
+				if err != nil {
+					return nil, fmt.Errorf("rule %s: invalid regular expression for argument %q: %w", rule.label(i), name, err)
+				}
+				predicate.matches = re
+				rule.Arguments[name] = predicate
+			}
+		}
+	}
+
+	return &policy, nil
+}
+
+// Evaluate decides whether a call is allowed and explains why.
+func (p *Policy) Evaluate(call Call) Decision {
+	for i, rule := range p.Rules {
+		if !rule.matches(call) {
+			continue
+		}
+
+		explanation := fmt.Sprintf("%s by rule %s", pastTense(rule.Action), rule.label(i))
+		if rule.Reason != "" {
+			explanation += ": " + rule.Reason
+		} else if conditions := rule.describe(); conditions != "" {
+			explanation += " (" + conditions + ")"
+		}
+
+		return Decision{
+			Allowed:     rule.Action == ActionAllow,
+			Rule:        i,
+			Explanation: explanation,
+		}
+	}
+
+	return Decision{
+		Allowed:     p.Default == ActionAllow,
+		Rule:        -1,
+		Explanation: fmt.Sprintf("%s by default: no rule matches", pastTense(p.Default)),
+	}
+}
+
+func pastTense(action string) string {
+	if action == ActionDeny {
+		return "denied"
+	}
+	return "allowed"
+}
+
+func (r *Rule) label(index int) string {
+	if r.Name != "" {
+		return fmt.Sprintf("%q", r.Name)
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+func (r *Rule) matches(call Call) bool {
+	if !matchGlob(r.Server, call.Server) {
+		return false
+	}
+	if len(r.Tools) > 0 && !matchAnyGlob(r.Tools, call.Tool) {
+		return false
+	}
+	if len(r.Clients) > 0 && !matchAnyGlob(r.Clients, call.Client) {
+		return false
+	}
+
+	for name, predicate := range r.Arguments {
+		deny := r.Action == ActionDeny
+		value, found := call.Arguments[name]
+		if !found {
+			if !deny || predicate.Optional {
+				return false
+			}
+			continue
+		}
+		if !predicate.holds(value, deny) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *Rule) describe() string {
+	var conditions []string
+	for name, predicate := range r.Arguments {
+		for _, condition := range predicate.describe() {
+			conditions = append(conditions, fmt.Sprintf("%q %s", name, condition))
+		}
+	}
+	sort.Strings(conditions)
+	return strings.Join(conditions, ", ")
+}
+
+// holds checks the predicate against the value of an argument, failing closed
+// for deny rules.
+func (p Predicate) holds(value any, deny bool) bool {
+	if items, ok := value.([]any); ok && p.Equals == nil && len(p.OneOf) == 0 {
+		for _, item := range items {
+			if p.holds(item, deny) == deny {
+				return deny
+			}
+		}
+		return !deny && len(items) > 0
+	}
+
+	if p.Equals != nil && !sameValue(p.Equals, value) {
+		return false
+	}
+	if len(p.OneOf) > 0 {
+		found := false
+		for _, candidate := range p.OneOf {
+			if sameValue(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if p.Under == "" && p.NotUnder == "" && p.Contains == "" && p.NotContains == "" && p.Matches == "" {
+		return true
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return deny
+	}
+
+	if (p.Under != "" || p.NotUnder != "") && !isAbs(s) {
+		return deny
+	}
+	if p.Under != "" && !isUnder(s, p.Under) {
+		return false
+	}
+	if p.NotUnder != "" && isUnder(s, p.NotUnder) {
+		return false
+	}
+	if p.Contains != "" && !strings.Contains(strings.ToLower(s), strings.ToLower(p.Contains)) {
+		return false
+	}
+	if p.NotContains != "" && strings.Contains(strings.ToLower(s), strings.ToLower(p.NotContains)) {
+		return false
+	}
+	if p.Matches != "" {
+		re := p.matches
+		if re == nil {
+			re = regexp.MustCompile(p.Matches)
+		}
+		if !re.MatchString(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p Predicate) describe() []string {
+	var conditions []string
+	if p.Under != "" {
+		conditions = append(conditions, fmt.Sprintf("is under %s", p.Under))
+	}
+	if p.NotUnder != "" {
+		conditions = append(conditions, fmt.Sprintf("is not under %s", p.NotUnder))
+	}
+	if p.Contains != "" {
+		conditions = append(conditions, fmt.Sprintf("contains %q", p.Contains))
+	}
+	if p.NotContains != "" {
+		conditions = append(conditions, fmt.Sprintf("doesn't contain %q", p.NotContains))
+	}
+	if p.Matches != "" {
+		conditions = append(conditions, fmt.Sprintf("matches %s", p.Matches))
+	}
+	if p.Equals != nil {
+		conditions = append(conditions, fmt.Sprintf("equals %v", p.Equals))
+	}
+	if len(p.OneOf) > 0 {
+		conditions = append(conditions, fmt.Sprintf("is one of %v", p.OneOf))
+	}
+	return conditions
+}
+
+// isUnder checks whether an absolute path is a directory or is inside a
+// directory, once both are cleaned. This prevents escaping the directory with
+// `..`. Relative paths depend on the working directory of the MCP server, so
+// they are never under anything.
+func isUnder(value, dir string) bool {
+	if !isAbs(value) || !isAbs(dir) {
+		return false
+	}
+	value = path.Clean(filepath.ToSlash(value))
+	dir = path.Clean(filepath.ToSlash(dir))
+
+	return value == dir || dir == "/" || strings.HasPrefix(value, strings.TrimSuffix(dir, "/")+"/")
+}
+
+func isAbs(value string) bool {
+	return path.IsAbs(filepath.ToSlash(value)) || filepath.IsAbs(value)
+}
+
+// sameValue compares values coming either from YAML or from JSON, where numbers
+// can have different types.
+func sameValue(expected, actual any) bool {
+	if e, ok := toFloat(expected); ok {
+		a, ok := toFloat(actual)
+		return ok && a == e
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func matchGlob(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, err := filepath.Match(pattern, value)
+	return err == nil && matched
+}
+
+func matchAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
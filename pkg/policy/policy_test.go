@@ -0,0 +1,166 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+rules:
+  - name: no-drop
+    action: deny
+    server: postgres
+    tools: [query, "execute_*"]
+    arguments:
+      sql:
+        contains: DROP
+    reason: Dropping tables is not allowed
+  - action: allow
+    server: filesystem
+    arguments:
+      path:
+        under: /workspace
+  - action: deny
+    server: filesystem
+  - action: deny
+    clients: ["cursor*"]
+    tools: ["delete_*"]
+  - action: allow
+    server: github
+    arguments:
+      state:
+        oneOf: [open, closed]
+      limit:
+        equals: 10
+`
+
+func TestEvaluate(t *testing.T) {
+	policy, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		call    Call
+		allowed bool
+		rule    int
+	}{
+		{"drop is denied", Call{Server: "postgres", Tool: "query", Arguments: map[string]any{"sql": "drop table users"}}, false, 0},
+		{"select is allowed", Call{Server: "postgres", Tool: "execute_sql", Arguments: map[string]any{"sql": "SELECT 1"}}, true, -1},
+		{"path under workspace", Call{Server: "filesystem", Tool: "read_file", Arguments: map[string]any{"path": "/workspace/src/main.go"}}, true, 1},
+		{"path escaping workspace", Call{Server: "filesystem", Tool: "read_file", Arguments: map[string]any{"path": "/workspace/../etc/passwd"}}, false, 2},
+		{"all paths under workspace", Call{Server: "filesystem", Tool: "read_files", Arguments: map[string]any{"path": []any{"/workspace/a", "/workspace/b"}}}, true, 1},
+		{"one path outside workspace", Call{Server: "filesystem", Tool: "read_files", Arguments: map[string]any{"path": []any{"/workspace/a", "/tmp/b"}}}, false, 2},
+		{"missing path", Call{Server: "filesystem", Tool: "list_allowed_directories"}, false, 2},
+		{"client glob", Call{Server: "notion", Tool: "delete_page", Client: "cursor-vscode"}, false, 3},
+		{"other client", Call{Server: "notion", Tool: "delete_page", Client: "claude-ai"}, true, -1},
+		{"oneOf and equals", Call{Server: "github", Tool: "list_issues", Arguments: map[string]any{"state": "open", "limit": float64(10)}}, true, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decision := policy.Evaluate(test.call)
+			assert.Equal(t, test.allowed, decision.Allowed, decision.Explanation)
+			assert.Equal(t, test.rule, decision.Rule, decision.Explanation)
+		})
+	}
+}
+
+func TestDenyRulesFailClosed(t *testing.T) {
+	policy, err := Parse([]byte(`
+rules:
+  - action: deny
+    tools: [query]
+    arguments:
+      sql:
+        contains: DROP
+  - action: deny
+    tools: [read_file]
+    arguments:
+      path:
+        under: /etc
+  - action: allow
+    tools: [write_file]
+    arguments:
+      path:
+        under: /workspace
+  - action: deny
+    tools: [write_file]
+  - action: deny
+    tools: [list_dir]
+    arguments:
+      path:
+        notUnder: /workspace
+  - action: deny
+    tools: [search]
+    arguments:
+      scope:
+        equals: all
+        optional: true
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		call    Call
+		allowed bool
+	}{
+		{"one item of a list matches", Call{Tool: "query", Arguments: map[string]any{"sql": []any{"select 1", "DROP TABLE x"}}}, false},
+		{"no item of a list matches", Call{Tool: "query", Arguments: map[string]any{"sql": []any{"select 1", "select 2"}}}, true},
+		{"not a string", Call{Tool: "query", Arguments: map[string]any{"sql": map[string]any{"text": "DROP TABLE x"}}}, false},
+		{"list with an item that's not a string", Call{Tool: "query", Arguments: map[string]any{"sql": []any{"select 1", float64(1)}}}, false},
+		{"absolute path outside of the denied directory", Call{Tool: "read_file", Arguments: map[string]any{"path": "/home/user/notes.txt"}}, true},
+		{"relative path", Call{Tool: "read_file", Arguments: map[string]any{"path": "../../etc/passwd"}}, false},
+		{"relative path for an allow rule", Call{Tool: "write_file", Arguments: map[string]any{"path": "workspace/a"}}, false},
+		{"absolute path for an allow rule", Call{Tool: "write_file", Arguments: map[string]any{"path": "/workspace/a"}}, true},
+		{"missing argument for an allow rule", Call{Tool: "write_file"}, false},
+		{"missing argument", Call{Tool: "list_dir"}, false},
+		{"path under the allowed directory", Call{Tool: "list_dir", Arguments: map[string]any{"path": "/workspace/src"}}, true},
+		{"missing optional argument", Call{Tool: "search"}, true},
+		{"optional argument", Call{Tool: "search", Arguments: map[string]any{"scope": "all"}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decision := policy.Evaluate(test.call)
+			assert.Equal(t, test.allowed, decision.Allowed, decision.Explanation)
+		})
+	}
+}
+
+func TestExplanation(t *testing.T) {
+	policy, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	decision := policy.Evaluate(Call{Server: "postgres", Tool: "query", Arguments: map[string]any{"sql": "DROP TABLE users"}})
+	assert.Equal(t, `denied by rule "no-drop": Dropping tables is not allowed`, decision.Explanation)
+
+	decision = policy.Evaluate(Call{Server: "filesystem", Tool: "read_file", Arguments: map[string]any{"path": "/workspace/a"}})
+	assert.Equal(t, `allowed by rule #2 ("path" is under /workspace)`, decision.Explanation)
+
+	decision = policy.Evaluate(Call{Server: "other", Tool: "tool"})
+	assert.Equal(t, "allowed by default: no rule matches", decision.Explanation)
+}
+
+func TestDefaultDeny(t *testing.T) {
+	policy, err := Parse([]byte("default: deny\nrules:\n  - action: allow\n    server: github\n"))
+	require.NoError(t, err)
+
+	assert.True(t, policy.Evaluate(Call{Server: "github", Tool: "list_issues"}).Allowed)
+	assert.False(t, policy.Evaluate(Call{Server: "notion", Tool: "search"}).Allowed)
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	_, err := Parse([]byte("rules:\n  - action: block\n"))
+	require.ErrorContains(t, err, "invalid action")
+
+	_, err = Parse([]byte("rules:\n  - action: deny\n    arguments:\n      sql:\n        matches: '('\n"))
+	require.ErrorContains(t, err, "invalid regular expression")
+
+	_, err = Parse([]byte("rules:\n  - action: allow\n    arguments:\n      path:\n        under: workspace\n"))
+	require.ErrorContains(t, err, "must be absolute")
+
+	_, err = Parse([]byte("default: maybe\n"))
+	require.ErrorContains(t, err, "invalid default action")
+}
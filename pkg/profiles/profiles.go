@@ -0,0 +1,111 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles maps clients, identified by the name they give when they initialize
+// a session, to the subset of servers and tools they can use.
+type Profiles struct {
+	// Default is the name of the profile used by clients that match no profile.
+	// When it's empty, those clients can use every tool.
+	Default  string    `yaml:"default,omitempty"`
+	Profiles []Profile `yaml:"profiles"`
+}
+
+// Profile selects servers and tools. Empty lists select everything.
+type Profile struct {
+	Name    string   `yaml:"name"`
+	Clients []string `yaml:"clients,omitempty"`
+	Servers []string `yaml:"servers,omitempty"`
+	// Tools are patterns matching either the tool name or `server:tool`. Tools
+	// are known by the name clients see, after any override.
+	Tools []string `yaml:"tools,omitempty"`
+}
+
+func Read(file string) (*Profiles, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := Parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing profiles %s: %w", file, err)
+	}
+
+	return profiles, nil
+}
+
+func Parse(buf []byte) (*Profiles, error) {
+	var profiles Profiles
+	if err := yaml.Unmarshal(buf, &profiles); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, profile := range profiles.Profiles {
+		if profile.Name == "" {
+			return nil, errors.New("a profile has no name")
+		}
+		if names[profile.Name] {
+			return nil, fmt.Errorf("duplicate profile %q", profile.Name)
+		}
+		names[profile.Name] = true
+
+		for _, pattern := range append(append(append([]string{}, profile.Clients...), profile.Servers...), profile.Tools...) {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("profile %q: invalid pattern %q: %w", profile.Name, pattern, err)
+			}
+		}
+	}
+
+	if profiles.Default != "" && !names[profiles.Default] {
+		return nil, fmt.Errorf("unknown default profile %q", profiles.Default)
+	}
+
+	return &profiles, nil
+}
+
+// ForClient returns the first profile that matches a client name, or the
+// default profile. It returns nil when the client isn't restricted.
+func (p *Profiles) ForClient(clientName string) *Profile {
+	for i := range p.Profiles {
+		if matchAny(p.Profiles[i].Clients, clientName) {
+			return &p.Profiles[i]
+		}
+	}
+
+	for i := range p.Profiles {
+		if p.Profiles[i].Name == p.Default {
+			return &p.Profiles[i]
+		}
+	}
+
+	return nil
+}
+
+// Allows checks whether a tool, from a given server, is part of the profile.
+func (p *Profile) Allows(serverName, toolName string) bool {
+	if len(p.Servers) > 0 && !matchAny(p.Servers, serverName) {
+		return false
+	}
+	if len(p.Tools) > 0 && !matchAny(p.Tools, toolName) && !matchAny(p.Tools, serverName+":"+toolName) {
+		return false
+	}
+	return true
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, err := filepath.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
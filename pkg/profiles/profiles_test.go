@@ -0,0 +1,63 @@
+package profiles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProfiles = `
+default: minimal
+profiles:
+  - name: vscode
+    clients: ["Visual Studio Code*"]
+    servers: [github-official]
+  - name: desktop
+    clients: [claude-ai]
+    servers: [filesystem, playwright]
+    tools: ["read_*", "playwright:*"]
+  - name: minimal
+    tools: [search]
+`
+
+func TestForClient(t *testing.T) {
+	profiles, err := Parse([]byte(testProfiles))
+	require.NoError(t, err)
+
+	assert.Equal(t, "vscode", profiles.ForClient("Visual Studio Code - Insiders").Name)
+	assert.Equal(t, "desktop", profiles.ForClient("claude-ai").Name)
+	assert.Equal(t, "minimal", profiles.ForClient("cursor").Name)
+
+	profiles.Default = ""
+	assert.Nil(t, profiles.ForClient("cursor"))
+}
+
+func TestAllows(t *testing.T) {
+	profiles, err := Parse([]byte(testProfiles))
+	require.NoError(t, err)
+
+	vscode := profiles.ForClient("Visual Studio Code")
+	assert.True(t, vscode.Allows("github-official", "create_issue"))
+	assert.False(t, vscode.Allows("filesystem", "read_file"))
+
+	desktop := profiles.ForClient("claude-ai")
+	assert.True(t, desktop.Allows("filesystem", "read_file"))
+	assert.False(t, desktop.Allows("filesystem", "write_file"))
+	assert.True(t, desktop.Allows("playwright", "browser_navigate"))
+	assert.False(t, desktop.Allows("github-official", "read_file"))
+}
+
+func TestInvalidProfiles(t *testing.T) {
+	_, err := Parse([]byte("profiles:\n  - clients: [a]\n"))
+	require.ErrorContains(t, err, "no name")
+
+	_, err = Parse([]byte("profiles:\n  - name: a\n  - name: a\n"))
+	require.ErrorContains(t, err, "duplicate profile")
+
+	_, err = Parse([]byte("default: b\nprofiles:\n  - name: a\n"))
+	require.ErrorContains(t, err, "unknown default profile")
+
+	_, err = Parse([]byte("profiles:\n  - name: a\n    tools: [\"[\"]\n"))
+	require.ErrorContains(t, err, "invalid pattern")
+}
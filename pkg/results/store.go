@@ -0,0 +1,270 @@
+package results
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotFound = errors.New("result not found or expired")
+	ErrTooLarge = errors.New("result larger than the store")
+)
+
+// sweepInterval is how often expired results are removed, at most.
+const sweepInterval = time.Minute
+
+// Result is a tool result, or part of it, kept aside by the gateway.
+type Result struct {
+	MIMEType string
+	// Binary results are returned as blobs, in a single page.
+	Binary  bool
+	Data    []byte
+	Expires time.Time
+}
+
+// Store keeps results for a limited time, either in memory or on disk.
+// Each result can only be read by the session that produced it. The total
+// size of the results is capped: the oldest results are removed to make room
+// for new ones.
+type Store struct {
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	size    int64
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type entry struct {
+	session  string
+	mimeType string
+	binary   bool
+	data     []byte // When stored in memory
+	path     string // When stored on disk
+	size     int64
+	expires  time.Time
+}
+
+// NewStore creates a store. Results are kept in memory when dir is empty.
+// There's no limit on the total size of the results when maxSize is 0.
+func NewStore(dir string, ttl time.Duration, maxSize int64) (*Store, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("invalid ttl %s: must be positive", ttl)
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Store{
+		dir:     dir,
+		ttl:     ttl,
+		maxSize: maxSize,
+		now:     time.Now,
+		entries: map[string]*entry{},
+		stop:    make(chan struct{}),
+	}
+	go s.sweep(min(ttl, sweepInterval))
+
+	return s, nil
+}
+
+// sweep removes the expired results, even when the store isn't used.
+func (s *Store) sweep(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.expireLocked()
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Store) Put(session string, result Result) (string, Result, error) {
+	size := int64(len(result.Data))
+	if s.maxSize > 0 && size > s.maxSize {
+		return "", Result{}, ErrTooLarge
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", Result{}, err
+	}
+
+	e := &entry{
+		session:  session,
+		mimeType: result.MIMEType,
+		binary:   result.Binary,
+		size:     size,
+		expires:  s.now().Add(s.ttl),
+	}
+	if s.dir == "" {
+		e.data = result.Data
+	} else {
+		e.path = filepath.Join(s.dir, id)
+		if err := os.WriteFile(e.path, result.Data, 0o600); err != nil {
+			return "", Result{}, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireLocked()
+	for s.maxSize > 0 && s.size+size > s.maxSize {
+		s.removeOldestLocked()
+	}
+	s.entries[id] = e
+	s.size += size
+
+	result.Expires = e.expires
+	return id, result, nil
+}
+
+func (s *Store) Get(session, id string) (Result, error) {
+	s.mu.Lock()
+	s.expireLocked()
+	e, found := s.entries[id]
+	s.mu.Unlock()
+
+	if !found || e.session != session {
+		return Result{}, ErrNotFound
+	}
+
+	data := e.data
+	if e.path != "" {
+		var err error
+		if data, err = os.ReadFile(e.path); err != nil {
+			return Result{}, err
+		}
+	}
+
+	return Result{
+		MIMEType: e.mimeType,
+		Binary:   e.binary,
+		Data:     data,
+		Expires:  e.expires,
+	}, nil
+}
+
+// RemoveSession removes the results of a session, once it's closed.
+func (s *Store) RemoveSession(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, e := range s.entries {
+		if e.session == session {
+			s.removeLocked(id, e)
+		}
+	}
+}
+
+// Close removes every result.
+func (s *Store) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, e := range s.entries {
+		s.removeLocked(id, e)
+	}
+	return nil
+}
+
+func (s *Store) expireLocked() {
+	now := s.now()
+	for id, e := range s.entries {
+		if now.After(e.expires) {
+			s.removeLocked(id, e)
+		}
+	}
+}
+
+func (s *Store) removeOldestLocked() {
+	var (
+		oldestID string
+		oldest   *entry
+	)
+	for id, e := range s.entries {
+		if oldest == nil || e.expires.Before(oldest.expires) {
+			oldestID, oldest = id, e
+		}
+	}
+	if oldest != nil {
+		s.removeLocked(oldestID, oldest)
+	}
+}
+
+func (s *Store) removeLocked(id string, e *entry) {
+	if e.path != "" {
+		_ = os.Remove(e.path)
+	}
+	delete(s.entries, id)
+	s.size -= e.size
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Pages returns the number of pages of a text, for a given page size in bytes.
+func Pages(text []byte, pageSize int) int {
+	return len(splitPages(text, pageSize))
+}
+
+// Page returns one page of a text, numbered from 1. Pages never split a UTF-8 character.
+func Page(text []byte, pageSize, page int) ([]byte, bool) {
+	pages := splitPages(text, pageSize)
+	if page < 1 || page > len(pages) {
+		return nil, false
+	}
+	return pages[page-1], true
+}
+
+func splitPages(text []byte, pageSize int) [][]byte {
+	if pageSize <= 0 || len(text) <= pageSize {
+		return [][]byte{text}
+	}
+
+	var pages [][]byte
+	for len(text) > 0 {
+		end := min(pageSize, len(text))
+		for end < len(text) && end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == 0 {
+			end = min(pageSize, len(text))
+		}
+		pages = append(pages, text[:end])
+		text = text[end:]
+	}
+	return pages
+}
@@ -0,0 +1,135 @@
+package results
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	for name, dir := range map[string]string{"memory": "", "disk": t.TempDir()} {
+		t.Run(name, func(t *testing.T) {
+			store, err := NewStore(dir, time.Minute, 0)
+			require.NoError(t, err)
+
+			now := time.Now()
+			store.now = func() time.Time { return now }
+
+			id, stored, err := store.Put("session1", Result{MIMEType: "text/plain", Data: []byte("large result")})
+			require.NoError(t, err)
+			assert.Equal(t, now.Add(time.Minute), stored.Expires)
+
+			result, err := store.Get("session1", id)
+			require.NoError(t, err)
+			assert.Equal(t, "large result", string(result.Data))
+			assert.Equal(t, "text/plain", result.MIMEType)
+
+			// Other sessions can't read the result.
+			_, err = store.Get("session2", id)
+			require.ErrorIs(t, err, ErrNotFound)
+
+			// Results expire.
+			now = now.Add(2 * time.Minute)
+			_, err = store.Get("session1", id)
+			require.ErrorIs(t, err, ErrNotFound)
+
+			if dir != "" {
+				files, err := os.ReadDir(dir)
+				require.NoError(t, err)
+				assert.Empty(t, files)
+			}
+		})
+	}
+}
+
+func TestStoreMaxSize(t *testing.T) {
+	store, err := NewStore("", time.Minute, 10)
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	first, _, err := store.Put("session", Result{Data: []byte("123456")})
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	second, _, err := store.Put("session", Result{Data: []byte("1234")})
+	require.NoError(t, err)
+
+	// The oldest result makes room for the new one.
+	now = now.Add(time.Second)
+	_, _, err = store.Put("session", Result{Data: []byte("12345")})
+	require.NoError(t, err)
+	_, err = store.Get("session", first)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get("session", second)
+	require.NoError(t, err)
+
+	_, _, err = store.Put("session", Result{Data: []byte("12345678901")})
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestStoreRemoveSession(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.Minute, 0)
+	require.NoError(t, err)
+	defer store.Close()
+
+	closed, _, err := store.Put("closed", Result{Data: []byte("result")})
+	require.NoError(t, err)
+	open, _, err := store.Put("open", Result{Data: []byte("result")})
+	require.NoError(t, err)
+
+	store.RemoveSession("closed")
+
+	_, err = store.Get("closed", closed)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get("open", open)
+	require.NoError(t, err)
+}
+
+func TestStoreSweep(t *testing.T) {
+	store, err := NewStore(t.TempDir(), 10*time.Millisecond, 0)
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, _, err = store.Put("session", Result{Data: []byte("result")})
+	require.NoError(t, err)
+
+	// Expired results are removed without any other call to the store.
+	assert.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.entries) == 0 && store.size == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPages(t *testing.T) {
+	text := []byte("héllo wörld")
+
+	assert.Equal(t, 1, Pages(text, 100))
+	assert.Equal(t, 1, Pages(text, 0))
+	assert.Equal(t, 5, Pages(text, 3))
+
+	var joined []byte
+	for page := 1; page <= Pages(text, 3); page++ {
+		content, ok := Page(text, 3, page)
+		require.True(t, ok)
+		assert.LessOrEqual(t, len(content), 3)
+		joined = append(joined, content...)
+	}
+	assert.Equal(t, text, joined)
+
+	_, ok := Page(text, 3, 6)
+	assert.False(t, ok)
+}
+
+func TestNewStoreInvalidTTL(t *testing.T) {
+	_, err := NewStore("", 0, 0)
+	require.ErrorContains(t, err, "must be positive")
+
+	_, err = NewStore("", -time.Minute, 0)
+	require.ErrorContains(t, err, "must be positive")
+}
@@ -39,12 +39,22 @@ var (
 	// ToolErrorCounter tracks tool call errors by type and server
 	ToolErrorCounter metric.Int64Counter
 
+	// ToolResultSize tracks the size of tool results, before and after they are transformed
+	ToolResultSize metric.Int64Histogram
+
+	// Virtual tool metrics
+	VirtualToolStepCounter  metric.Int64Counter
+	VirtualToolStepDuration metric.Float64Histogram
+
 	// GatewayStartCounter tracks gateway starts
 	GatewayStartCounter metric.Int64Counter
 
 	// InitializeCounter tracks initialize calls
 	InitializeCounter metric.Int64Counter
 
+	// ServerInitializeCounter tracks connections to MCP servers by negotiated protocol version
+	ServerInitializeCounter metric.Int64Counter
+
 	// ListToolsCounter tracks list tools calls
 	ListToolsCounter metric.Int64Counter
 
@@ -128,6 +138,36 @@ func Init() {
 		}
 	}
 
+	ToolResultSize, err = meter.Int64Histogram("mcp.tool.result.size",
+		metric.WithDescription("Size of tool results, before and after they are transformed"),
+		metric.WithUnit("By"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating tool result size histogram: %v\n", err)
+		}
+	}
+
+	VirtualToolStepCounter, err = meter.Int64Counter("mcp.virtual_tool.steps",
+		metric.WithDescription("Number of steps run by virtual tools"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating virtual tool step counter: %v\n", err)
+		}
+	}
+
+	VirtualToolStepDuration, err = meter.Float64Histogram("mcp.virtual_tool.step.duration",
+		metric.WithDescription("Duration of the steps run by virtual tools"),
+		metric.WithUnit("ms"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating virtual tool step duration histogram: %v\n", err)
+		}
+	}
+
 	GatewayStartCounter, err = meter.Int64Counter("mcp.gateway.starts",
 		metric.WithDescription("Number of gateway starts"),
 		metric.WithUnit("1"))
@@ -148,6 +188,16 @@ func Init() {
 		}
 	}
 
+	ServerInitializeCounter, err = meter.Int64Counter("mcp.server.initialize",
+		metric.WithDescription("Number of connections to MCP servers"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating server initialize counter: %v\n", err)
+		}
+	}
+
 	ListToolsCounter, err = meter.Int64Counter("mcp.list.tools",
 		metric.WithDescription("Number of list tools calls"),
 		metric.WithUnit("1"))
@@ -368,6 +418,41 @@ func StartToolCallSpan(ctx context.Context, toolName string, attrs ...attribute.
 		trace.WithSpanKind(trace.SpanKindClient))
 }
 
+// StartVirtualToolStepSpan starts a new span for a step of a virtual tool
+func StartVirtualToolStepSpan(ctx context.Context, virtualToolName string, step int, toolName string) (context.Context, trace.Span) {
+	if tracer == nil {
+		return ctx, trace.SpanFromContext(ctx) // Telemetry not initialized
+	}
+
+	return tracer.Start(ctx, "mcp.virtual_tool.step",
+		trace.WithAttributes(
+			attribute.String("mcp.virtual_tool.name", virtualToolName),
+			attribute.Int("mcp.virtual_tool.step", step),
+			attribute.String("mcp.tool.name", toolName),
+		),
+		trace.WithSpanKind(trace.SpanKindInternal))
+}
+
+// RecordVirtualToolStep records the outcome and duration of a step of a virtual tool
+func RecordVirtualToolStep(ctx context.Context, virtualToolName string, step int, toolName string, durationMs float64, success bool) {
+	if VirtualToolStepCounter == nil || VirtualToolStepDuration == nil {
+		return // Telemetry not initialized
+	}
+
+	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Virtual tool %s ran step %d (%s) in %.2fms, success=%v\n", virtualToolName, step, toolName, durationMs, success)
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String("mcp.virtual_tool.name", virtualToolName),
+		attribute.Int("mcp.virtual_tool.step", step),
+		attribute.String("mcp.tool.name", toolName),
+		attribute.Bool("mcp.virtual_tool.success", success),
+	)
+	VirtualToolStepCounter.Add(ctx, 1, attrs)
+	VirtualToolStepDuration.Record(ctx, durationMs, attrs)
+}
+
 // StartCommandSpan starts a new span for a command execution
 func StartCommandSpan(ctx context.Context, commandPath string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
 	// Add the command path as an attribute
@@ -506,6 +591,43 @@ func RecordInitialize(ctx context.Context, params *mcp.InitializeParams) {
 		))
 }
 
+// RecordServerInitialize records a connection to an MCP server and the protocol version it negotiated
+func RecordServerInitialize(ctx context.Context, serverName string, protocolVersion string) {
+	if ServerInitializeCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Server %s initialized with protocol version %s\n", serverName, protocolVersion)
+	}
+
+	ServerInitializeCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.server.origin", serverName),
+			attribute.String("mcp.protocol.version", protocolVersion),
+		))
+}
+
+// RecordToolResultTransform records the size of a tool result before and after it was transformed
+func RecordToolResultTransform(ctx context.Context, serverName string, toolName string, originalSize int, transformedSize int) {
+	if ToolResultSize == nil {
+		return // Telemetry not initialized
+	}
+
+	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Result of %s/%s transformed from %d to %d bytes\n", serverName, toolName, originalSize, transformedSize)
+	}
+
+	for stage, size := range map[string]int{"original": originalSize, "transformed": transformedSize} {
+		ToolResultSize.Record(ctx, int64(size),
+			metric.WithAttributes(
+				attribute.String("mcp.server.name", serverName),
+				attribute.String("mcp.tool.name", toolName),
+				attribute.String("mcp.result.stage", stage),
+			))
+	}
+}
+
 // RecordListTools records a list tools call
 func RecordListTools(ctx context.Context, clientName string) {
 	if ListToolsCounter == nil {
@@ -328,6 +328,69 @@ func TestRecordToolError(t *testing.T) {
 	assert.True(t, found, "tool error should be recorded")
 }
 
+func TestRecordServerInitialize(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	ctx := context.Background()
+	RecordServerInitialize(ctx, "test_server", "2025-06-18")
+
+	// Collect metrics
+	var rm metricdata.ResourceMetrics
+	err := metricReader.Collect(ctx, &rm)
+	require.NoError(t, err)
+
+	found := false
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "mcp.server.initialize" {
+				found = true
+				sum := m.Data.(metricdata.Sum[int64])
+				assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+				attrs := sum.DataPoints[0].Attributes
+
+				serverNameAttr, _ := attrs.Value(attribute.Key("mcp.server.origin"))
+				assert.Equal(t, "test_server", serverNameAttr.AsString())
+
+				versionAttr, _ := attrs.Value(attribute.Key("mcp.protocol.version"))
+				assert.Equal(t, "2025-06-18", versionAttr.AsString())
+			}
+		}
+	}
+	assert.True(t, found, "server initialize should be recorded")
+}
+
+func TestRecordToolResultTransform(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	ctx := context.Background()
+	RecordToolResultTransform(ctx, "github-official", "search_repositories", 50000, 2000)
+
+	// Collect metrics
+	var rm metricdata.ResourceMetrics
+	err := metricReader.Collect(ctx, &rm)
+	require.NoError(t, err)
+
+	sizes := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "mcp.tool.result.size" {
+				histogram := m.Data.(metricdata.Histogram[int64])
+				for _, dataPoint := range histogram.DataPoints {
+					toolAttr, _ := dataPoint.Attributes.Value(attribute.Key("mcp.tool.name"))
+					assert.Equal(t, "search_repositories", toolAttr.AsString())
+
+					stageAttr, _ := dataPoint.Attributes.Value(attribute.Key("mcp.result.stage"))
+					sizes[stageAttr.AsString()] = dataPoint.Sum
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"original": 50000, "transformed": 2000}, sizes)
+}
+
 func TestConcurrentMetricRecording(t *testing.T) {
 	_, metricReader := setupTestTelemetry(t)
 	Init()
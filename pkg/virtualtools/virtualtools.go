@@ -0,0 +1,171 @@
+package virtualtools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/eval"
+)
+
+// Config lists virtual tools: tools exposed by the gateway that run a sequence
+// of calls to the tools of MCP servers.
+type Config struct {
+	Tools []Tool `yaml:"tools"`
+}
+
+type Tool struct {
+	Name        string         `yaml:"name"`
+	Title       string         `yaml:"title,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	InputSchema map[string]any `yaml:"inputSchema,omitempty"`
+	Steps       []Step         `yaml:"steps"`
+	// Result is a template for the text returned by the virtual tool.
+	// By default, the result of the last step is returned.
+	Result string `yaml:"result,omitempty"`
+
+	schema *jsonschema.Schema
+}
+
+// Step calls a tool. Its arguments can be templated with the input of the
+// virtual tool, eg. `{{input.owner}}`, and with the results of previous steps,
+// eg. `{{steps.issue.json.title}}` or `{{steps.issue.text}}`.
+type Step struct {
+	ID string `yaml:"id,omitempty"`
+	// Server is only needed when several servers have a tool with the same name.
+	Server    string         `yaml:"server,omitempty"`
+	Tool      string         `yaml:"tool"`
+	Arguments map[string]any `yaml:"arguments,omitempty"`
+}
+
+func Read(file string) (*Config, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := Parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing virtual tools %s: %w", file, err)
+	}
+
+	return config, nil
+}
+
+func Parse(buf []byte) (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(buf, &config); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for i := range config.Tools {
+		tool := &config.Tools[i]
+		if tool.Name == "" {
+			return nil, errors.New("a virtual tool has no name")
+		}
+		if names[tool.Name] {
+			return nil, fmt.Errorf("duplicate virtual tool %q", tool.Name)
+		}
+		names[tool.Name] = true
+
+		if len(tool.Steps) == 0 {
+			return nil, fmt.Errorf("virtual tool %s has no steps", tool.Name)
+		}
+		ids := map[string]bool{}
+		for j, step := range tool.Steps {
+			if step.Tool == "" {
+				return nil, fmt.Errorf("virtual tool %s: step %d has no tool", tool.Name, j+1)
+			}
+			if step.ID != "" {
+				if ids[step.ID] {
+					return nil, fmt.Errorf("virtual tool %s: duplicate step id %q", tool.Name, step.ID)
+				}
+				ids[step.ID] = true
+			}
+		}
+
+		schema, err := parseSchema(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("virtual tool %s: invalid input schema: %w", tool.Name, err)
+		}
+		tool.schema = schema
+	}
+
+	return &config, nil
+}
+
+func parseSchema(value map[string]any) (*jsonschema.Schema, error) {
+	if len(value) == 0 {
+		return &jsonschema.Schema{Type: "object"}, nil
+	}
+
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(buf, &schema); err != nil {
+		return nil, err
+	}
+	if schema.Type != "object" {
+		return nil, fmt.Errorf("type must be object, not %q", schema.Type)
+	}
+
+	return &schema, nil
+}
+
+// Schema returns the input schema of the virtual tool.
+func (t *Tool) Schema() *jsonschema.Schema {
+	if t.schema == nil {
+		return &jsonschema.Schema{Type: "object"}
+	}
+	return t.schema
+}
+
+// StepLabel identifies a step in logs and errors.
+func (t *Tool) StepLabel(index int) string {
+	step := t.Steps[index]
+	if step.ID != "" {
+		return fmt.Sprintf("step %d (%s: %s)", index+1, step.ID, step.Tool)
+	}
+	return fmt.Sprintf("step %d (%s)", index+1, step.Tool)
+}
+
+// StepKey is how the results of a step are referenced by later steps.
+func (s *Step) StepKey(index int) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return fmt.Sprint(index + 1)
+}
+
+// Evaluate expands the templates found in the strings of a value, recursively.
+func Evaluate(value any, variables map[string]any) any {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v
+		}
+		return eval.Evaluate(v, variables)
+	case map[string]any:
+		evaluated := make(map[string]any, len(v))
+		for key, item := range v {
+			evaluated[key] = Evaluate(item, variables)
+		}
+		return evaluated
+	case []any:
+		evaluated := make([]any, len(v))
+		for i, item := range v {
+			evaluated[i] = Evaluate(item, variables)
+		}
+		return evaluated
+	default:
+		return value
+	}
+}
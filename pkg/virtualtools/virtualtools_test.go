@@ -0,0 +1,76 @@
+package virtualtools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+tools:
+  - name: triage_issue
+    description: Fetch an issue, search for related code and comment.
+    inputSchema:
+      type: object
+      properties:
+        number:
+          type: integer
+      required: [number]
+    steps:
+      - id: issue
+        tool: get_issue
+        arguments:
+          owner: myorg
+          number: "{{input.number}}"
+      - tool: search_code
+        arguments:
+          q: "{{steps.issue.json.title}} repo:myorg/app"
+    result: "Found: {{steps.2.text}}"
+`
+
+func TestParse(t *testing.T) {
+	config, err := Parse([]byte(testConfig))
+	require.NoError(t, err)
+	require.Len(t, config.Tools, 1)
+
+	tool := config.Tools[0]
+	assert.Equal(t, "object", tool.Schema().Type)
+	assert.Equal(t, []string{"number"}, tool.Schema().Required)
+	assert.Equal(t, "step 1 (issue: get_issue)", tool.StepLabel(0))
+	assert.Equal(t, "step 2 (search_code)", tool.StepLabel(1))
+	assert.Equal(t, "issue", tool.Steps[0].StepKey(0))
+	assert.Equal(t, "2", tool.Steps[1].StepKey(1))
+}
+
+func TestEvaluate(t *testing.T) {
+	variables := map[string]any{
+		"input": map[string]any{"number": 42.0},
+		"steps": map[string]any{
+			"issue": map[string]any{"json": map[string]any{"title": "Crash"}},
+		},
+	}
+
+	assert.Equal(t, map[string]any{
+		"owner":  "myorg",
+		"number": 42.0,
+		"q":      "Crash repo:myorg/app",
+		"labels": []any{"bug", "Crash"},
+	}, Evaluate(map[string]any{
+		"owner":  "myorg",
+		"number": "{{input.number}}",
+		"q":      "{{steps.issue.json.title}} repo:myorg/app",
+		"labels": []any{"bug", "{{steps.issue.json.title}}"},
+	}, variables))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, err := Parse([]byte("tools:\n  - name: a\n"))
+	require.ErrorContains(t, err, "has no steps")
+
+	_, err = Parse([]byte("tools:\n  - name: a\n    steps:\n      - id: x\n        tool: t\n      - id: x\n        tool: t\n"))
+	require.ErrorContains(t, err, "duplicate step id")
+
+	_, err = Parse([]byte("tools:\n  - name: a\n    inputSchema:\n      type: string\n    steps:\n      - tool: t\n"))
+	require.ErrorContains(t, err, "type must be object")
+}